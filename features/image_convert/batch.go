@@ -0,0 +1,244 @@
+package image_convert
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBatchWorkers is the number of images converted at once when no worker count is given.
+const DefaultBatchWorkers = 4
+
+// imageExtensions are the file extensions picked up from directories and globs.
+var imageExtensions = map[string]bool{
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".gif":  true,
+	".webp": true,
+	".bmp":  true,
+	".tif":  true,
+	".tiff": true,
+}
+
+// IsImageFile reports whether path has the extension of a supported image format.
+func IsImageFile(path string) bool {
+	return imageExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// BatchStatus is the state of a file in a batch conversion.
+type BatchStatus int
+
+const (
+	BatchPending BatchStatus = iota
+	BatchRunning
+	BatchDone
+	BatchFailed
+)
+
+// String returns the display name of the status.
+func (s BatchStatus) String() string {
+	switch s {
+	case BatchRunning:
+		return "converting"
+	case BatchDone:
+		return "done"
+	case BatchFailed:
+		return "failed"
+	default:
+		return "pending"
+	}
+}
+
+// BatchItem is a file of a batch conversion and its outcome.
+type BatchItem struct {
+	Path     string
+	Status   BatchStatus
+	Result   *Result
+	Err      error
+	Duration time.Duration
+}
+
+// BatchReport holds the outcome of every file of a batch conversion, in input order.
+type BatchReport struct {
+	Items []BatchItem
+}
+
+// Succeeded returns the number of files converted without errors.
+func (r *BatchReport) Succeeded() int {
+	count := 0
+	for _, item := range r.Items {
+		if item.Status == BatchDone {
+			count++
+		}
+	}
+	return count
+}
+
+// Failed returns the items that failed.
+func (r *BatchReport) Failed() []BatchItem {
+	var failed []BatchItem
+	for _, item := range r.Items {
+		if item.Status == BatchFailed {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// ExpandInputs resolves directories, globs and file paths into a sorted list of image files
+// without duplicates. Directories are not searched recursively.
+func ExpandInputs(inputs []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var paths []string
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	for _, input := range inputs {
+		input, err := expandPath(strings.TrimSpace(input))
+		if err != nil {
+			return nil, err
+		}
+		if input == "" {
+			continue
+		}
+
+		if info, err := os.Stat(input); err == nil {
+			if !info.IsDir() {
+				add(input)
+				continue
+			}
+			entries, err := os.ReadDir(input)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range entries {
+				if !entry.IsDir() && IsImageFile(entry.Name()) {
+					add(filepath.Join(input, entry.Name()))
+				}
+			}
+			continue
+		}
+
+		matches, err := filepath.Glob(input)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", input, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %q", input)
+		}
+		for _, match := range matches {
+			if IsImageFile(match) {
+				add(match)
+			}
+		}
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// batchNames returns a distinct output name for every input: the file name without extension,
+// followed by the extension when inputs share a name, and by a number when they still do.
+// Names are compared without case, as some file systems do.
+func batchNames(inputs []string) []string {
+	stems := make([]string, len(inputs))
+	counts := make(map[string]int)
+	for i, input := range inputs {
+		base := filepath.Base(input)
+		stems[i] = strings.TrimSuffix(base, filepath.Ext(base))
+		counts[strings.ToLower(stems[i])]++
+	}
+
+	names := make([]string, len(inputs))
+	used := make(map[string]bool)
+	for i, input := range inputs {
+		name := stems[i]
+		if ext := strings.TrimPrefix(filepath.Ext(input), "."); counts[strings.ToLower(name)] > 1 && ext != "" {
+			name += "-" + strings.ToLower(ext)
+		}
+		unique := name
+		for n := 2; used[strings.ToLower(unique)]; n++ {
+			unique = fmt.Sprintf("%s-%d", name, n)
+		}
+		used[strings.ToLower(unique)] = true
+		names[i] = unique
+	}
+	return names
+}
+
+// ConvertBatch converts every image in inputs with a pool of workers, DefaultBatchWorkers if workers <= 0.
+// Each file is written to "<OutputDir>/<name>/", or "./<name>/" if no output directory is set, where
+// name is the file name without extension, made distinct when inputs share it, see batchNames.
+// A failing file doesn't stop the others. onProgress, if not nil, is called whenever a file
+// starts or finishes, with the index of the item in the report.
+func ConvertBatch(inputs []string, opts Options, workers int, onProgress func(index int, item BatchItem)) *BatchReport {
+	if workers <= 0 {
+		workers = DefaultBatchWorkers
+	}
+
+	report := &BatchReport{Items: make([]BatchItem, len(inputs))}
+	for i, path := range inputs {
+		report.Items[i] = BatchItem{Path: path}
+	}
+
+	var mutex sync.Mutex
+	update := func(i int, change func(item *BatchItem)) {
+		mutex.Lock()
+		change(&report.Items[i])
+		item := report.Items[i]
+		mutex.Unlock()
+
+		if onProgress != nil {
+			onProgress(i, item)
+		}
+	}
+
+	names := batchNames(inputs)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(inputs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				update(i, func(item *BatchItem) { item.Status = BatchRunning })
+
+				fileOpts := opts
+				fileOpts.Name = names[i]
+				fileOpts.OutputDir = filepath.Join(opts.OutputDir, names[i])
+				if opts.OutputDir == "" {
+					fileOpts.OutputDir = "./" + names[i]
+				}
+
+				start := time.Now()
+				result, err := Convert(inputs[i], fileOpts)
+				update(i, func(item *BatchItem) {
+					item.Result = result
+					item.Err = err
+					item.Duration = time.Since(start)
+					item.Status = BatchDone
+					if err != nil {
+						item.Status = BatchFailed
+					}
+				})
+			}
+		}()
+	}
+
+	for i := range inputs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return report
+}
@@ -0,0 +1,68 @@
+package image_convert
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandInputs(t *testing.T) {
+	dir := t.TempDir()
+	a := writeTestPNG(t, dir, "a.png", 16)
+	b := writeTestPNG(t, dir, "b.png", 16)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0644))
+
+	paths, err := ExpandInputs([]string{dir, filepath.Join(dir, "*.png"), a})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{a, b}, paths)
+
+	_, err = ExpandInputs([]string{filepath.Join(dir, "*.jpg")})
+	assert.Error(t, err)
+}
+
+func TestConvertBatch(t *testing.T) {
+	dir := t.TempDir()
+	good := writeTestPNG(t, dir, "good.png", 32)
+	bad := filepath.Join(dir, "bad.png")
+	assert.NoError(t, os.WriteFile(bad, []byte("not an image"), 0644))
+	other := writeTestPNG(t, dir, "other.png", 32)
+
+	output := filepath.Join(dir, "out")
+	var mutex sync.Mutex
+	var updates int
+	report := ConvertBatch([]string{good, bad, other}, Options{OutputDir: output}, 2, func(index int, item BatchItem) {
+		mutex.Lock()
+		updates++
+		mutex.Unlock()
+	})
+
+	assert.Equal(t, 6, updates, "every file starts and finishes")
+	assert.Equal(t, 2, report.Succeeded())
+	assert.Len(t, report.Failed(), 1)
+	assert.Equal(t, bad, report.Failed()[0].Path)
+	assert.FileExists(t, filepath.Join(output, "good", "good.ico"))
+	assert.FileExists(t, filepath.Join(output, "other", "other.ico"))
+}
+
+func TestConvertBatchSharedNames(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "a"), 0755))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "b"), 0755))
+	inputs := []string{
+		writeTestPNG(t, dir, "a/logo.png", 16),
+		writeTestPNG(t, dir, "b/logo.png", 16),
+		writeTestPNG(t, dir, "logo.jpg", 16),
+		writeTestPNG(t, dir, "icon.png", 16),
+	}
+	assert.Equal(t, []string{"logo-png", "logo-png-2", "logo-jpg", "icon"}, batchNames(inputs))
+
+	output := filepath.Join(dir, "out")
+	report := ConvertBatch(inputs, Options{OutputDir: output}, 4, nil)
+	assert.Equal(t, 4, report.Succeeded())
+	for _, name := range batchNames(inputs) {
+		assert.FileExists(t, filepath.Join(output, name, name+".ico"))
+	}
+}
@@ -0,0 +1,325 @@
+package image_convert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	ico "github.com/Kodeworks/golang-image-ico"
+)
+
+// OutputFormat is a kind of output written by Convert.
+type OutputFormat string
+
+const (
+	FormatICO        OutputFormat = "ico"          // "<name>.ico" with every size.
+	FormatICOPerSize OutputFormat = "ico-per-size" // "<width>x<height>.ico" per size.
+	FormatPNG        OutputFormat = "png"          // "<width>x<height>.png" per size.
+	FormatICNS       OutputFormat = "icns"         // "<name>.icns" with every type in IcnsTypes.
+	FormatCUR        OutputFormat = "cur"          // "<name>.cur" with every size and Options.Hotspot.
+	FormatFavicon    OutputFormat = "favicon"      // Favicon bundle in "favicon/".
+)
+
+// OutputFormats lists the file formats, the names of IconSetPresets are valid formats too.
+var OutputFormats = []OutputFormat{FormatICO, FormatICOPerSize, FormatPNG, FormatICNS, FormatCUR, FormatFavicon}
+
+// ParseOutputFormat returns the output format with the given name, which may be an icon set preset.
+func ParseOutputFormat(name string) (OutputFormat, error) {
+	name = strings.ToLower(name)
+	for _, f := range OutputFormats {
+		if name == string(f) {
+			return f, nil
+		}
+	}
+	if _, err := GetIconSetPreset(name); err == nil {
+		return OutputFormat(name), nil
+	}
+	return "", fmt.Errorf("unknown output format %q", name)
+}
+
+// OverwritePolicy decides what happens when an output already exists.
+type OverwritePolicy int
+
+const (
+	Overwrite    OverwritePolicy = iota // Replace existing outputs.
+	SkipExisting                        // Keep existing outputs and list them as skipped.
+	FailExisting                        // Stop with ErrOutputExists.
+)
+
+// ErrOutputExists is returned when an output exists and the policy is FailExisting.
+var ErrOutputExists = errors.New("output already exists")
+
+// Options configures Convert.
+type Options struct {
+	Sizes     []image.Point // Icon sizes, Sizes if empty.
+	OutputDir string        // Directory the outputs are written to, "./<basename>" if empty.
+	Name      string        // Base name of the outputs, the input file name without extension if empty.
+	Resize    ResizeOptions // How the image is resized.
+	Overwrite OverwritePolicy
+	Formats   []OutputFormat // Outputs to write, FormatICO if empty.
+	Hotspot   Hotspot        // Cursor hotspot for FormatCUR, on the source image even with Effects.
+
+	// Effects are applied in order to the image fitted on a square canvas, before any output is resized.
+	Effects []Effect
+}
+
+// DefaultOptions returns the options used by the ConvertImageTo functions.
+func DefaultOptions() Options {
+	return Options{
+		Sizes:   Sizes,
+		Resize:  DefaultResizeOptions,
+		Formats: []OutputFormat{FormatICO},
+	}
+}
+
+// Result lists what Convert produced.
+type Result struct {
+	Format  string      // Format of the input image.
+	Size    image.Point // Size of the input image.
+	Files   []string    // Written files. Bundles and icon sets list every file.
+	Skipped []string    // Existing outputs kept because of SkipExisting.
+}
+
+// Convert decodes the image at inputPath and writes every output format in opts.
+func Convert(inputPath string, opts Options) (*Result, error) {
+	expandedInputPath, err := expandPath(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("error expanding input path: %w", err)
+	}
+
+	file, err := os.Open(expandedInputPath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening image: %w", err)
+	}
+	defer file.Close()
+
+	name := strings.TrimSuffix(filepath.Base(expandedInputPath), filepath.Ext(expandedInputPath))
+	if opts.Name == "" {
+		opts.Name = name
+	}
+	if opts.OutputDir == "" {
+		opts.OutputDir = "./" + name
+	}
+
+	return ConvertReader(file, opts)
+}
+
+// ConvertReader decodes an image from r and writes every output format in opts.
+// Options.Name defaults to "icon" and Options.OutputDir to the current directory.
+func ConvertReader(r io.Reader, opts Options) (*Result, error) {
+	img, format, err := DecodeImage(r)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %w", err)
+	}
+
+	result, err := ConvertImage(img, opts)
+	if result != nil {
+		result.Format = format
+	}
+	return result, err
+}
+
+// ConvertImage writes every output format in opts for an already decoded image.
+func ConvertImage(img image.Image, opts Options) (*Result, error) {
+	opts = withDefaults(opts)
+	result := &Result{Size: img.Bounds().Size()}
+
+	hotspot, err := effectHotspot(img, opts)
+	if err != nil && slices.Contains(opts.Formats, FormatCUR) {
+		return result, err
+	}
+	opts.Hotspot = hotspot
+	img, err = applyEffects(img, opts)
+	if err != nil {
+		return result, err
+	}
+
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return result, fmt.Errorf("error creating directory: %w", err)
+	}
+
+	for _, format := range opts.Formats {
+		if err := convertFormat(img, format, opts, result); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// Encode writes img to w in a single file format: FormatICO, FormatCUR, FormatICNS or FormatPNG.
+// PNG output uses the first size of the options.
+func Encode(w io.Writer, img image.Image, format OutputFormat, opts Options) error {
+	opts = withDefaults(opts)
+
+	hotspot, err := effectHotspot(img, opts)
+	if err != nil && format == FormatCUR {
+		return err
+	}
+	img, err = applyEffects(img, opts)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatICO:
+		return EncodeIco(w, resizeAll(img, opts))
+	case FormatCUR:
+		return EncodeCur(w, img, opts.Sizes, hotspot, opts.Resize)
+	case FormatICNS:
+		return EncodeIcns(w, img, opts.Resize)
+	case FormatPNG:
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		return encoder.Encode(w, Resize(img, opts.Sizes[0], opts.Resize))
+	default:
+		return fmt.Errorf("%s can't be written to a single stream", format)
+	}
+}
+
+// withDefaults fills in the empty fields of opts.
+func withDefaults(opts Options) Options {
+	if len(opts.Sizes) == 0 {
+		opts.Sizes = Sizes
+	}
+	if opts.Name == "" {
+		opts.Name = "icon"
+	}
+	if opts.OutputDir == "" {
+		opts.OutputDir = "."
+	}
+	if len(opts.Formats) == 0 {
+		opts.Formats = []OutputFormat{FormatICO}
+	}
+	return opts
+}
+
+// applyEffects fits img on a square canvas and applies the effects of opts, if there are any.
+func applyEffects(img image.Image, opts Options) (image.Image, error) {
+	if len(opts.Effects) == 0 {
+		return img, nil
+	}
+	canvas := Resize(img, image.Pt(effectCanvasSize, effectCanvasSize), opts.Resize)
+	return ApplyEffects(canvas, opts.Effects)
+}
+
+// resizeAll resizes img to every size of the options.
+func resizeAll(img image.Image, opts Options) []image.Image {
+	images := make([]image.Image, len(opts.Sizes))
+	for i, size := range opts.Sizes {
+		images[i] = Resize(img, size, opts.Resize)
+	}
+	return images
+}
+
+// convertFormat writes a single output format and adds the written files to result.
+func convertFormat(img image.Image, format OutputFormat, opts Options, result *Result) error {
+	switch format {
+	case FormatICO:
+		path := filepath.Join(opts.OutputDir, opts.Name+".ico")
+		return writeOutput(path, opts.Overwrite, result, func(w io.Writer) error {
+			return EncodeIco(w, resizeAll(img, opts))
+		})
+
+	case FormatCUR:
+		path := filepath.Join(opts.OutputDir, opts.Name+".cur")
+		return writeOutput(path, opts.Overwrite, result, func(w io.Writer) error {
+			return EncodeCur(w, img, opts.Sizes, opts.Hotspot, opts.Resize)
+		})
+
+	case FormatICNS:
+		path := filepath.Join(opts.OutputDir, opts.Name+".icns")
+		return writeOutput(path, opts.Overwrite, result, func(w io.Writer) error {
+			return EncodeIcns(w, img, opts.Resize)
+		})
+
+	case FormatICOPerSize, FormatPNG:
+		for _, size := range opts.Sizes {
+			resized := Resize(img, size, opts.Resize)
+			path := filepath.Join(opts.OutputDir, fmt.Sprintf("%dx%d.%s", size.X, size.Y, strings.TrimSuffix(string(format), "-per-size")))
+			err := writeOutput(path, opts.Overwrite, result, func(w io.Writer) error {
+				if format == FormatPNG {
+					encoder := png.Encoder{CompressionLevel: png.BestCompression}
+					return encoder.Encode(w, resized)
+				}
+				return ico.Encode(w, resized)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case FormatFavicon:
+		dir := filepath.Join(opts.OutputDir, "favicon")
+		return writeBundle(dir, opts.Overwrite, result, func() ([]string, error) {
+			return GenerateFavicons(img, dir, opts.Name, opts.Resize)
+		})
+
+	default:
+		preset, err := GetIconSetPreset(string(format))
+		if err != nil {
+			return fmt.Errorf("unknown output format %q", format)
+		}
+		dir := filepath.Join(opts.OutputDir, preset.Name)
+		return writeBundle(dir, opts.Overwrite, result, func() ([]string, error) {
+			return preset.Generate(img, dir, opts.Name, opts.Resize)
+		})
+	}
+}
+
+// writeOutput writes the file at path with encode, according to the overwrite policy.
+func writeOutput(path string, policy OverwritePolicy, result *Result, encode func(w io.Writer) error) error {
+	if skip, err := checkExisting(path, policy); err != nil || skip {
+		if skip {
+			result.Skipped = append(result.Skipped, path)
+		}
+		return err
+	}
+
+	// Encode in memory first so a failed encode doesn't leave a broken file.
+	var buf bytes.Buffer
+	if err := encode(&buf); err != nil {
+		return fmt.Errorf("error encoding %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	result.Files = append(result.Files, path)
+	return nil
+}
+
+// writeBundle runs generate for an output made of many files, applying the overwrite policy to its directory.
+func writeBundle(dir string, policy OverwritePolicy, result *Result, generate func() ([]string, error)) error {
+	if skip, err := checkExisting(dir, policy); err != nil || skip {
+		if skip {
+			result.Skipped = append(result.Skipped, dir)
+		}
+		return err
+	}
+
+	paths, err := generate()
+	result.Files = append(result.Files, paths...)
+	return err
+}
+
+// checkExisting reports whether the output at path should be skipped, or fails if it exists and the policy forbids it.
+func checkExisting(path string, policy OverwritePolicy) (bool, error) {
+	if policy == Overwrite {
+		return false, nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return false, nil
+	}
+	if policy == SkipExisting {
+		return true, nil
+	}
+	return false, fmt.Errorf("%s: %w", path, ErrOutputExists)
+}
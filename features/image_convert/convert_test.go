@@ -0,0 +1,82 @@
+package image_convert
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestPNG writes a test image to dir and returns its path.
+func writeTestPNG(t *testing.T, dir, name string, size int) string {
+	path := filepath.Join(dir, name)
+	if err := savePNG(path, testImage(size)); err != nil {
+		t.Fatalf("Failed to write test image: %v", err)
+	}
+	return path
+}
+
+func TestConvert(t *testing.T) {
+	dir := t.TempDir()
+	input := writeTestPNG(t, dir, "logo.png", 64)
+	output := filepath.Join(dir, "out")
+
+	opts := Options{
+		Sizes:     []image.Point{{16, 16}, {32, 32}},
+		OutputDir: output,
+		Formats:   []OutputFormat{FormatICO, FormatPNG, FormatICNS},
+	}
+	result, err := Convert(input, opts)
+	if err != nil {
+		t.Fatalf("Failed to convert: %v", err)
+	}
+
+	assert.Equal(t, "png", result.Format)
+	assert.Equal(t, image.Pt(64, 64), result.Size)
+	assert.Equal(t, []string{
+		filepath.Join(output, "logo.ico"),
+		filepath.Join(output, "16x16.png"),
+		filepath.Join(output, "32x32.png"),
+		filepath.Join(output, "logo.icns"),
+	}, result.Files)
+
+	// Existing outputs are kept or rejected depending on the policy.
+	opts.Overwrite = SkipExisting
+	result, err = Convert(input, opts)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Files)
+	assert.Len(t, result.Skipped, 4)
+
+	opts.Overwrite = FailExisting
+	_, err = Convert(input, opts)
+	assert.ErrorIs(t, err, ErrOutputExists)
+}
+
+func TestConvertReaderAndEncode(t *testing.T) {
+	var input bytes.Buffer
+	assert.NoError(t, png.Encode(&input, testImage(64)))
+
+	dir := t.TempDir()
+	result, err := ConvertReader(&input, Options{OutputDir: dir, Formats: []OutputFormat{"linux"}})
+	if err != nil {
+		t.Fatalf("Failed to convert: %v", err)
+	}
+	assert.FileExists(t, filepath.Join(dir, "linux", "hicolor", "16x16", "apps", "icon.png"))
+	assert.Len(t, result.Files, len(LinuxIconSizes)+1)
+
+	var out bytes.Buffer
+	assert.NoError(t, Encode(&out, testImage(64), FormatPNG, Options{Sizes: []image.Point{{24, 24}}}))
+	img, err := png.Decode(&out)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Pt(24, 24), img.Bounds().Size())
+
+	assert.Error(t, Encode(&out, testImage(64), FormatFavicon, Options{}))
+	_, err = ParseOutputFormat("ios")
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "icon.ico"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,141 @@
+package image_convert
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Hotspot is the click point of a cursor, relative to the source image. Either as
+// pixel offsets from its top left corner, or as normalized coordinates from 0 to 1.
+type Hotspot struct {
+	X, Y       float64
+	Normalized bool
+}
+
+// ParseHotspot parses "x,y" pixel offsets or "x%,y%" percentages, for example "4,2" or "50%,50%".
+func ParseHotspot(s string) (Hotspot, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return Hotspot{}, fmt.Errorf("invalid hotspot %q, expected x,y", s)
+	}
+
+	var h Hotspot
+	var values [2]float64
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		percent := strings.HasSuffix(part, "%")
+		if i > 0 && percent != h.Normalized {
+			return Hotspot{}, fmt.Errorf("invalid hotspot %q, mixes pixels and percentages", s)
+		}
+		h.Normalized = percent
+
+		v, err := strconv.ParseFloat(strings.TrimSuffix(part, "%"), 64)
+		if err != nil {
+			return Hotspot{}, fmt.Errorf("invalid hotspot %q: %w", s, err)
+		}
+		if percent {
+			v /= 100
+		}
+		values[i] = v
+	}
+	h.X, h.Y = values[0], values[1]
+	return h, nil
+}
+
+// CursorHotspot returns the hotspot of an output of the given size, following how img is
+// fitted into it. It fails if the hotspot falls outside the output, for example in a crop.
+func CursorHotspot(img image.Image, size image.Point, hotspot Hotspot, opts ResizeOptions) (image.Point, error) {
+	b := img.Bounds()
+	x, y, err := hotspotPixels(b.Size(), hotspot)
+	if err != nil {
+		return image.Point{}, err
+	}
+
+	src, dst := FitBounds(img, size, opts)
+	px, py := fitPoint(src.Sub(b.Min), dst, x, y)
+
+	p := image.Pt(int(math.Floor(px)), int(math.Floor(py)))
+	// A hotspot on the right or bottom edge of the image is on its last pixel.
+	if x == float64(b.Dx()) {
+		p.X = dst.Max.X - 1
+	}
+	if y == float64(b.Dy()) {
+		p.Y = dst.Max.Y - 1
+	}
+	if !p.In(image.Rect(0, 0, size.X, size.Y)) {
+		return image.Point{}, fmt.Errorf("hotspot is outside the %dx%d cursor", size.X, size.Y)
+	}
+	return p, nil
+}
+
+// hotspotPixels returns the hotspot as pixel offsets in an image of the given size.
+func hotspotPixels(size image.Point, hotspot Hotspot) (x, y float64, err error) {
+	x, y = hotspot.X, hotspot.Y
+	if hotspot.Normalized {
+		x *= float64(size.X)
+		y *= float64(size.Y)
+	}
+	if x < 0 || y < 0 || x > float64(size.X) || y > float64(size.Y) {
+		return 0, 0, fmt.Errorf("hotspot %g,%g is outside the %dx%d image", x, y, size.X, size.Y)
+	}
+	return x, y, nil
+}
+
+// fitPoint moves a point of the src rectangle to where it is drawn in dst.
+func fitPoint(src, dst image.Rectangle, x, y float64) (float64, float64) {
+	return float64(dst.Min.X) + (x-float64(src.Min.X))*float64(dst.Dx())/float64(src.Dx()),
+		float64(dst.Min.Y) + (y-float64(src.Min.Y))*float64(dst.Dy())/float64(src.Dy())
+}
+
+// effectHotspot returns the hotspot of opts, given on img, as pixels on the canvas applyEffects
+// draws: fitted like the image and moved inwards by every padding effect.
+func effectHotspot(img image.Image, opts Options) (Hotspot, error) {
+	if len(opts.Effects) == 0 {
+		return opts.Hotspot, nil
+	}
+
+	b := img.Bounds()
+	x, y, err := hotspotPixels(b.Size(), opts.Hotspot)
+	if err != nil {
+		return Hotspot{}, err
+	}
+
+	src, dst := FitBounds(img, image.Pt(effectCanvasSize, effectCanvasSize), opts.Resize)
+	src = src.Sub(b.Min)
+	if x < float64(src.Min.X) || y < float64(src.Min.Y) || x > float64(src.Max.X) || y > float64(src.Max.Y) {
+		return Hotspot{}, fmt.Errorf("hotspot %g,%g is cropped out of the image", x, y)
+	}
+	x, y = fitPoint(src, dst, x, y)
+
+	for _, effect := range opts.Effects {
+		if effect.Type != EffectPadding {
+			continue
+		}
+		// The same margin as Effect.Apply, the canvas being square.
+		margin := math.Round(effect.Amount * effectCanvasSize)
+		scale := (effectCanvasSize - 2*margin) / effectCanvasSize
+		x, y = margin+x*scale, margin+y*scale
+	}
+	return Hotspot{X: x, Y: y}, nil
+}
+
+// EncodeCur writes img as a CUR file with one entry per size, each with the hotspot scaled to its size.
+func EncodeCur(w io.Writer, img image.Image, sizes []image.Point, hotspot Hotspot, opts ResizeOptions) error {
+	entries := make([]icoEntry, len(sizes))
+	for i, size := range sizes {
+		p, err := CursorHotspot(img, size, hotspot, opts)
+		if err != nil {
+			return err
+		}
+		entries[i] = icoEntry{
+			img:      Resize(img, size, opts),
+			planes:   uint16(p.X),
+			bitCount: uint16(p.Y),
+		}
+	}
+	return encodeIconDir(w, icoTypeCursor, entries)
+}
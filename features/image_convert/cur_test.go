@@ -0,0 +1,85 @@
+package image_convert
+
+import (
+	"bytes"
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHotspot(t *testing.T) {
+	h, err := ParseHotspot("4, 2")
+	assert.NoError(t, err)
+	assert.Equal(t, Hotspot{X: 4, Y: 2}, h)
+
+	h, err = ParseHotspot("50%,25%")
+	assert.NoError(t, err)
+	assert.Equal(t, Hotspot{X: 0.5, Y: 0.25, Normalized: true}, h)
+
+	_, err = ParseHotspot("50%,2")
+	assert.Error(t, err)
+	_, err = ParseHotspot("1")
+	assert.Error(t, err)
+}
+
+func TestCursorHotspot(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 32))
+
+	// Contain centers the wide image vertically.
+	p, err := CursorHotspot(img, image.Pt(32, 32), Hotspot{X: 32, Y: 16}, ResizeOptions{Mode: Contain})
+	assert.NoError(t, err)
+	assert.Equal(t, image.Pt(16, 16), p)
+
+	p, err = CursorHotspot(img, image.Pt(32, 32), Hotspot{X: 1, Y: 1, Normalized: true}, ResizeOptions{Mode: Stretch})
+	assert.NoError(t, err)
+	assert.Equal(t, image.Pt(31, 31), p)
+
+	// Cover crops the left and right quarters away.
+	_, err = CursorHotspot(img, image.Pt(32, 32), Hotspot{X: 2, Y: 2}, ResizeOptions{Mode: Cover})
+	assert.Error(t, err)
+
+	_, err = CursorHotspot(img, image.Pt(32, 32), Hotspot{X: 100, Y: 2}, ResizeOptions{})
+	assert.Error(t, err)
+}
+
+func TestEncodeCur(t *testing.T) {
+	var buf bytes.Buffer
+	sizes := []image.Point{{16, 16}, {32, 32}}
+	if err := EncodeCur(&buf, testImage(64), sizes, Hotspot{X: 0.5, Y: 0.25, Normalized: true}, DefaultResizeOptions); err != nil {
+		t.Fatalf("Failed to encode CUR: %v", err)
+	}
+
+	file, err := DecodeIco(&buf)
+	if err != nil {
+		t.Fatalf("Failed to decode CUR: %v", err)
+	}
+	assert.True(t, file.Cursor)
+	assert.Equal(t, image.Pt(8, 4), file.Entries[0].Hotspot)
+	assert.Equal(t, image.Pt(16, 8), file.Entries[1].Hotspot)
+	assert.Equal(t, 32, file.Entries[0].BitCount)
+}
+
+func TestEncodeCurEffectsHotspot(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Sizes = []image.Point{{32, 32}}
+	opts.Resize.Mode = Contain
+	opts.Effects = []Effect{{Type: EffectPadding, Amount: 0.25}}
+	opts.Hotspot = Hotspot{X: 0, Y: 0} // The top left pixel of the wide source image.
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, image.NewRGBA(image.Rect(0, 0, 64, 32)), FormatCUR, opts); err != nil {
+		t.Fatalf("Failed to encode CUR: %v", err)
+	}
+	file, err := DecodeIco(&buf)
+	if err != nil {
+		t.Fatalf("Failed to decode CUR: %v", err)
+	}
+
+	// Centered vertically on the canvas, then shrunk by a quarter on every side.
+	assert.Equal(t, image.Pt(8, 12), file.Entries[0].Hotspot)
+
+	opts.Resize.Mode = Cover
+	opts.Hotspot = Hotspot{X: 2, Y: 2}
+	assert.Error(t, Encode(&buf, image.NewRGBA(image.Rect(0, 0, 64, 32)), FormatCUR, opts), "cropped out")
+}
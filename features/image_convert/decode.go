@@ -0,0 +1,251 @@
+package image_convert
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os"
+
+	// Register the decoders used by image.Decode and image.DecodeConfig.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+// SupportedFormats lists the names of the image formats that can be decoded.
+var SupportedFormats = []string{"png", "jpeg", "gif", "webp", "bmp", "tiff"}
+
+// ErrUnsupportedFormat is returned when an image is not in one of the SupportedFormats.
+var ErrUnsupportedFormat = errors.New("unsupported image format")
+
+// DetectFormat returns the format name of the image in data, as registered with the image package.
+func DetectFormat(data []byte) (string, error) {
+	_, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return "", ErrUnsupportedFormat
+	}
+	if err != nil {
+		return "", err
+	}
+	return format, nil
+}
+
+// DecodeImage decodes an image in any of the SupportedFormats and returns it with its format name.
+// JPEGs are rotated and flipped according to their EXIF orientation.
+func DecodeImage(r io.Reader) (image.Image, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	format, err := DetectFormat(data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, format, fmt.Errorf("error decoding %s image: %w", format, err)
+	}
+
+	if format == "jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+
+	return img, format, nil
+}
+
+// exifOrientationTag is the EXIF tag holding the image orientation.
+const exifOrientationTag = 0x0112
+
+// jpegOrientation returns the EXIF orientation (1-8) of a JPEG, or 1 if it has none.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xff || data[1] != 0xd8 {
+		return 1
+	}
+
+	// Walk the marker segments up to the start of the image data.
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xff {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xda || marker == 0xd9 { // Start of scan or end of image.
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return 1
+		}
+
+		segment := data[i+4 : end]
+		if marker == 0xe1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i = end
+	}
+
+	return 1
+}
+
+// exifOrientation reads the orientation tag from the first IFD of a TIFF structured EXIF block.
+func exifOrientation(tiff []byte) int {
+	order, offset := exifOrientationOffset(tiff)
+	if offset < 0 {
+		return 1
+	}
+	o := int(order.Uint16(tiff[offset:]))
+	if o < 1 || o > 8 {
+		return 1
+	}
+	return o
+}
+
+// exifOrientationOffset returns the byte order of a TIFF structured EXIF block and the offset
+// of the orientation value in its first IFD, or -1 if it has none.
+func exifOrientationOffset(tiff []byte) (binary.ByteOrder, int) {
+	if len(tiff) < 8 {
+		return nil, -1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, -1
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset+2 > len(tiff) {
+		return order, -1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return order, -1
+		}
+		if order.Uint16(tiff[entry:]) == exifOrientationTag {
+			return order, entry + 8
+		}
+	}
+
+	return order, -1
+}
+
+// applyOrientation returns img transformed so that it displays upright for the given EXIF orientation.
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	// source returns the source pixel shown at x, y of the output.
+	var source func(x, y int) (int, int)
+	dw, dh := w, h
+	switch orientation {
+	case 2: // Flipped horizontally.
+		source = func(x, y int) (int, int) { return w - 1 - x, y }
+	case 3: // Rotated 180°.
+		source = func(x, y int) (int, int) { return w - 1 - x, h - 1 - y }
+	case 4: // Flipped vertically.
+		source = func(x, y int) (int, int) { return x, h - 1 - y }
+	case 5: // Transposed.
+		source = func(x, y int) (int, int) { return y, x }
+	case 6: // Needs a 90° clockwise rotation.
+		source = func(x, y int) (int, int) { return y, h - 1 - x }
+	case 7: // Transversed.
+		source = func(x, y int) (int, int) { return w - 1 - y, h - 1 - x }
+	case 8: // Needs a 90° counter-clockwise rotation.
+		source = func(x, y int) (int, int) { return w - 1 - y, x }
+	}
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			sx, sy := source(x, y)
+			out.Set(x, y, img.At(b.Min.X+sx, b.Min.Y+sy))
+		}
+	}
+
+	return out
+}
+
+// maxJPEGHeaderSize is how much of a JPEG ImageSize reads to find its EXIF orientation,
+// enough for the largest APP1 segment.
+const maxJPEGHeaderSize = 1 << 17
+
+// ImageSize returns the width and height of the image at path as DecodeImage returns it,
+// swapped for JPEGs with a rotating EXIF orientation, without decoding all of it.
+func ImageSize(path string) (image.Point, error) {
+	expandedPath, err := expandPath(path)
+	if err != nil {
+		return image.Point{}, err
+	}
+
+	file, err := os.Open(expandedPath)
+	if err != nil {
+		return image.Point{}, err
+	}
+	defer file.Close()
+
+	config, format, err := image.DecodeConfig(file)
+	if errors.Is(err, image.ErrFormat) {
+		return image.Point{}, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return image.Point{}, err
+	}
+
+	size := image.Pt(config.Width, config.Height)
+	if format == "jpeg" {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return image.Point{}, err
+		}
+		header, err := io.ReadAll(io.LimitReader(file, maxJPEGHeaderSize))
+		if err != nil {
+			return image.Point{}, err
+		}
+		// Orientations 5 to 8 are transposed, see applyOrientation.
+		if jpegOrientation(header) >= 5 {
+			size = image.Pt(size.Y, size.X)
+		}
+	}
+	return size, nil
+}
+
+// DecodeFile decodes the image at path, see DecodeImage.
+func DecodeFile(path string) (image.Image, error) {
+	expandedPath, err := expandPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(expandedPath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening image: %w", err)
+	}
+	defer file.Close()
+
+	img, _, err := DecodeImage(file)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %w", err)
+	}
+	return img, nil
+}
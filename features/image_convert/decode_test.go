@@ -0,0 +1,113 @@
+package image_convert
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+)
+
+// withOrientation inserts an EXIF APP1 segment with the given orientation after the JPEG SOI marker.
+func withOrientation(jpg []byte, orientation uint16) []byte {
+	var tiffData bytes.Buffer
+	tiffData.WriteString("MM")
+	binary.Write(&tiffData, binary.BigEndian, uint16(42))
+	binary.Write(&tiffData, binary.BigEndian, uint32(8))
+	binary.Write(&tiffData, binary.BigEndian, uint16(1))
+	binary.Write(&tiffData, binary.BigEndian, [6]uint16{exifOrientationTag, 3, 0, 1, orientation, 0})
+	binary.Write(&tiffData, binary.BigEndian, uint32(0))
+
+	segment := append([]byte("Exif\x00\x00"), tiffData.Bytes()...)
+
+	var out bytes.Buffer
+	out.Write(jpg[:2])
+	out.Write([]byte{0xff, 0xe1})
+	binary.Write(&out, binary.BigEndian, uint16(len(segment)+2))
+	out.Write(segment)
+	out.Write(jpg[2:])
+	return out.Bytes()
+}
+
+func TestDecodeImageFormats(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 4))
+	encoders := map[string]func(*bytes.Buffer) error{
+		"jpeg": func(b *bytes.Buffer) error { return jpeg.Encode(b, img, nil) },
+		"gif":  func(b *bytes.Buffer) error { return gif.Encode(b, img, nil) },
+		"bmp":  func(b *bytes.Buffer) error { return bmp.Encode(b, img) },
+		"tiff": func(b *bytes.Buffer) error { return tiff.Encode(b, img, nil) },
+	}
+
+	for name, encode := range encoders {
+		var buf bytes.Buffer
+		if err := encode(&buf); err != nil {
+			t.Fatalf("Failed to encode %s: %v", name, err)
+		}
+
+		decoded, format, err := DecodeImage(&buf)
+		assert.NoError(t, err, name)
+		assert.Equal(t, name, format)
+		assert.Equal(t, image.Pt(8, 4), decoded.Bounds().Size(), name)
+	}
+
+	_, _, err := DecodeImage(bytes.NewReader([]byte("not an image")))
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestDecodeImageExifOrientation(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, color.White) // Left half white, right half black.
+		}
+	}
+	for x := 8; x < 16; x++ {
+		for y := 0; y < 8; y++ {
+			img.Set(x, y, color.Black)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatalf("Failed to encode JPEG: %v", err)
+	}
+	data := withOrientation(buf.Bytes(), 6)
+	assert.Equal(t, 6, jpegOrientation(data))
+
+	decoded, _, err := DecodeImage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Failed to decode JPEG: %v", err)
+	}
+
+	// Rotated clockwise, the white half ends up at the top.
+	assert.Equal(t, image.Pt(8, 16), decoded.Bounds().Size())
+	top, _, _, _ := decoded.At(4, 2).RGBA()
+	bottom, _, _, _ := decoded.At(4, 13).RGBA()
+	assert.Greater(t, top, uint32(0xf000))
+	assert.Less(t, bottom, uint32(0x1000))
+
+	// The size without decoding matches the decoded image.
+	path := filepath.Join(t.TempDir(), "rotated.jpg")
+	assert.NoError(t, os.WriteFile(path, data, 0644))
+	size, err := ImageSize(path)
+	assert.NoError(t, err)
+	assert.Equal(t, decoded.Bounds().Size(), size)
+}
+
+func TestDecodeFile(t *testing.T) {
+	dir := t.TempDir()
+	img, err := DecodeFile(writeTestPNG(t, dir, "logo.png", 16))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 16, 16), img.Bounds())
+
+	_, err = DecodeFile(filepath.Join(dir, "missing.png"))
+	assert.Error(t, err)
+}
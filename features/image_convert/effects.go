@@ -0,0 +1,256 @@
+package image_convert
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/draw"
+)
+
+// effectCanvasSize is the size of the square canvas effects are applied on by Convert,
+// the largest icon written, so every output is a downscale of it.
+const effectCanvasSize = 1024
+
+// EffectType is a kind of step in an effects pipeline.
+type EffectType string
+
+const (
+	EffectRoundCorners EffectType = "round"      // Rounds the corners with Radius.
+	EffectCircle       EffectType = "circle"     // Masks everything outside the inscribed circle.
+	EffectPadding      EffectType = "padding"    // Shrinks the image by Amount on every side.
+	EffectBackground   EffectType = "background" // Fills the transparent pixels with Color.
+	EffectBorder       EffectType = "border"     // Draws an Amount wide Color border with corner Radius.
+	EffectShadow       EffectType = "shadow"     // Draws a Color shadow blurred by Amount, moved by the offsets.
+)
+
+// EffectTypes lists every effect type.
+var EffectTypes = []EffectType{EffectRoundCorners, EffectCircle, EffectPadding, EffectBackground, EffectBorder, EffectShadow}
+
+// Effect is a step in an effects pipeline. Lengths are fractions of the shortest side of the
+// image, so a pipeline looks the same at any size.
+type Effect struct {
+	Type    EffectType `json:"type"`
+	Radius  float64    `json:"radius,omitempty"`  // Corner radius, 0.5 for a circle.
+	Amount  float64    `json:"amount,omitempty"`  // Padding, border width or shadow blur.
+	Color   string     `json:"color,omitempty"`   // Color in a format accepted by ParseColor.
+	OffsetX float64    `json:"offsetX,omitempty"` // Horizontal shadow offset.
+	OffsetY float64    `json:"offsetY,omitempty"` // Vertical shadow offset.
+}
+
+// defaultShadowColor is the shadow color used when an EffectShadow has none.
+var defaultShadowColor = color.NRGBA{A: 128}
+
+// ApplyEffects returns a copy of img with every effect applied in order.
+func ApplyEffects(img image.Image, effects []Effect) (*image.RGBA, error) {
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(out, out.Bounds(), img, b.Min, draw.Src)
+
+	for i, effect := range effects {
+		var err error
+		if out, err = effect.Apply(out); err != nil {
+			return nil, fmt.Errorf("effect %d: %w", i+1, err)
+		}
+	}
+	return out, nil
+}
+
+// Apply returns a copy of img with the effect applied.
+func (e Effect) Apply(img *image.RGBA) (*image.RGBA, error) {
+	short := float64(min(img.Bounds().Dx(), img.Bounds().Dy()))
+
+	switch e.Type {
+	case EffectRoundCorners:
+		return roundedRectMask(img, e.Radius*short), nil
+
+	case EffectCircle:
+		return circleMask(img), nil
+
+	case EffectPadding:
+		if e.Amount < 0 || e.Amount >= 0.5 {
+			return nil, fmt.Errorf("padding %g is outside [0, 0.5)", e.Amount)
+		}
+		return pad(img, int(math.Round(e.Amount*short))), nil
+
+	case EffectBackground:
+		c, err := effectColor(e.Color, nil)
+		if err != nil {
+			return nil, err
+		}
+		if c == nil {
+			return nil, fmt.Errorf("background needs a color")
+		}
+		return flatten(img, c), nil
+
+	case EffectBorder:
+		c, err := effectColor(e.Color, color.White)
+		if err != nil {
+			return nil, err
+		}
+		return border(img, e.Amount*short, e.Radius*short, c), nil
+
+	case EffectShadow:
+		c, err := effectColor(e.Color, defaultShadowColor)
+		if err != nil {
+			return nil, err
+		}
+		offset := image.Pt(int(math.Round(e.OffsetX*short)), int(math.Round(e.OffsetY*short)))
+		return dropShadow(img, e.Amount*short, offset, c), nil
+
+	default:
+		return nil, fmt.Errorf("unknown effect %q", e.Type)
+	}
+}
+
+// effectColor parses s, returning fallback if it is empty.
+func effectColor(s string, fallback color.Color) (color.Color, error) {
+	if s == "" {
+		return fallback, nil
+	}
+	return ParseColor(s)
+}
+
+// roundedRectCoverage returns how much of the pixel centered on x, y is inside a w by h
+// rounded rectangle at the origin with corner radius r, anti-aliased over one pixel.
+func roundedRectCoverage(x, y, w, h, r float64) float64 {
+	if w <= 0 || h <= 0 {
+		return 0
+	}
+	r = math.Max(0, math.Min(r, math.Min(w, h)/2))
+	// Signed distance from the pixel center to the rounded rectangle.
+	qx := math.Abs(x-w/2) - (w/2 - r)
+	qy := math.Abs(y-h/2) - (h/2 - r)
+	d := math.Hypot(math.Max(qx, 0), math.Max(qy, 0)) + math.Min(math.Max(qx, qy), 0) - r
+	return math.Max(0, math.Min(1, 0.5-d))
+}
+
+// roundedRectMask returns img with the corners outside radius made transparent.
+func roundedRectMask(img *image.RGBA, radius float64) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+	w, h := float64(b.Dx()), float64(b.Dy())
+
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			coverage := roundedRectCoverage(float64(x)+0.5, float64(y)+0.5, w, h, radius)
+			if coverage == 0 {
+				continue
+			}
+			i := img.PixOffset(b.Min.X+x, b.Min.Y+y)
+			for c := 0; c < 4; c++ {
+				out.Pix[i+c] = uint8(math.Round(float64(img.Pix[i+c]) * coverage))
+			}
+		}
+	}
+
+	return out
+}
+
+// pad returns img shrunk into the same bounds, leaving a transparent margin on every side.
+func pad(img *image.RGBA, margin int) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+	inner := b.Inset(margin)
+	if inner.Empty() {
+		return out
+	}
+	Lanczos.Interpolator().Scale(out, inner, img, b, draw.Src, nil)
+	return out
+}
+
+// border returns img with a border of the given width drawn inside a rounded rectangle
+// with corner radius, over the image.
+func border(img *image.RGBA, width, radius float64, c color.Color) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+	draw.Draw(out, b, img, b.Min, draw.Src)
+	if width <= 0 {
+		return out
+	}
+
+	w, h := float64(b.Dx()), float64(b.Dy())
+	mask := image.NewAlpha(b)
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			// The inner edge is the outer one inset by width, with a matching radius.
+			cx, cy := float64(x)+0.5, float64(y)+0.5
+			outer := roundedRectCoverage(cx, cy, w, h, radius)
+			inner := roundedRectCoverage(cx-width, cy-width, w-2*width, h-2*width, radius-width)
+			mask.Pix[mask.PixOffset(b.Min.X+x, b.Min.Y+y)] = uint8(math.Round(math.Max(0, outer-inner) * 255))
+		}
+	}
+
+	draw.DrawMask(out, b, image.NewUniform(c), image.Point{}, mask, b.Min, draw.Over)
+	return out
+}
+
+// dropShadow returns img drawn over its own silhouette in color c, blurred and moved by offset.
+// The shadow is clipped to the bounds, so the image needs padding for it to show.
+func dropShadow(img *image.RGBA, blur float64, offset image.Point, c color.Color) *image.RGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	alpha := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			alpha[y*w+x] = float64(img.Pix[img.PixOffset(b.Min.X+x, b.Min.Y+y)+3]) / 255
+		}
+	}
+	// Three box blurs approximate a gaussian blur.
+	if r := int(math.Round(blur / 3)); r > 0 {
+		for i := 0; i < 3; i++ {
+			boxBlur(alpha, w, h, r)
+		}
+	}
+
+	shadow := image.NewAlpha(b)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			sx, sy := x-offset.X, y-offset.Y
+			if sx < 0 || sy < 0 || sx >= w || sy >= h {
+				continue
+			}
+			shadow.Pix[shadow.PixOffset(b.Min.X+x, b.Min.Y+y)] = uint8(math.Round(alpha[sy*w+sx] * 255))
+		}
+	}
+
+	out := image.NewRGBA(b)
+	draw.DrawMask(out, b, image.NewUniform(c), image.Point{}, shadow, b.Min, draw.Src)
+	draw.Draw(out, b, img, b.Min, draw.Over)
+	return out
+}
+
+// boxBlur blurs the w by h values in place with a box of the given radius, horizontally
+// then vertically. Values outside the bounds count as 0.
+func boxBlur(values []float64, w, h, radius int) {
+	size := float64(2*radius + 1)
+	line := make([]float64, max(w, h))
+
+	blur := func(n int, at func(i int) *float64) {
+		for i := 0; i < n; i++ {
+			line[i] = *at(i)
+		}
+		var sum float64
+		for i := 0; i < radius && i < n; i++ {
+			sum += line[i]
+		}
+		for i := 0; i < n; i++ {
+			if j := i + radius; j < n {
+				sum += line[j]
+			}
+			if j := i - radius - 1; j >= 0 {
+				sum -= line[j]
+			}
+			*at(i) = sum / size
+		}
+	}
+
+	for y := 0; y < h; y++ {
+		blur(w, func(x int) *float64 { return &values[y*w+x] })
+	}
+	for x := 0; x < w; x++ {
+		blur(h, func(y int) *float64 { return &values[y*w+x] })
+	}
+}
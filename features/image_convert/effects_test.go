@@ -0,0 +1,120 @@
+package image_convert
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func solidImage(size int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+func alphaAt(img *image.RGBA, x, y int) uint8 {
+	return img.RGBAAt(x, y).A
+}
+
+func TestApplyEffectsRoundCorners(t *testing.T) {
+	out, err := ApplyEffects(solidImage(64, color.White), []Effect{{Type: EffectRoundCorners, Radius: 0.25}})
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(0), alphaAt(out, 0, 0))
+	assert.Equal(t, uint8(0), alphaAt(out, 63, 63))
+	assert.Equal(t, uint8(255), alphaAt(out, 32, 0), "edges between the corners are kept")
+	assert.Equal(t, uint8(255), alphaAt(out, 32, 32))
+}
+
+func TestApplyEffectsCircle(t *testing.T) {
+	out, err := ApplyEffects(solidImage(64, color.White), []Effect{{Type: EffectCircle}})
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(0), alphaAt(out, 4, 4))
+	assert.Equal(t, uint8(255), alphaAt(out, 32, 32))
+}
+
+func TestApplyEffectsPaddingAndBackground(t *testing.T) {
+	out, err := ApplyEffects(solidImage(64, color.RGBA{R: 255, A: 255}), []Effect{
+		{Type: EffectPadding, Amount: 0.25},
+		{Type: EffectBackground, Color: "#0000ff"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, out.RGBAAt(4, 4), "the margin is filled with the background")
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, out.RGBAAt(32, 32))
+	assert.Equal(t, image.Rect(0, 0, 64, 64), out.Bounds())
+}
+
+func TestApplyEffectsBorder(t *testing.T) {
+	out, err := ApplyEffects(solidImage(64, color.RGBA{R: 255, A: 255}), []Effect{
+		{Type: EffectBorder, Amount: 0.125, Color: "#00ff00"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, color.RGBA{G: 255, A: 255}, out.RGBAAt(2, 32))
+	assert.Equal(t, color.RGBA{G: 255, A: 255}, out.RGBAAt(32, 61))
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, out.RGBAAt(32, 32))
+}
+
+func TestApplyEffectsShadow(t *testing.T) {
+	out, err := ApplyEffects(solidImage(64, color.White), []Effect{
+		{Type: EffectPadding, Amount: 0.25},
+		{Type: EffectShadow, Amount: 0.05, OffsetX: 0.125, OffsetY: 0.125, Color: "#000000"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(0), alphaAt(out, 2, 2))
+	assert.Equal(t, color.RGBA{A: 255}, out.RGBAAt(52, 52), "the shadow is moved down and right")
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, out.RGBAAt(32, 32), "the image is drawn over its shadow")
+}
+
+func TestApplyEffectsInvalid(t *testing.T) {
+	img := solidImage(16, color.White)
+	_, err := ApplyEffects(img, []Effect{{Type: "blur"}})
+	assert.Error(t, err)
+	_, err = ApplyEffects(img, []Effect{{Type: EffectBackground}})
+	assert.Error(t, err)
+	_, err = ApplyEffects(img, []Effect{{Type: EffectPadding, Amount: 0.5}})
+	assert.Error(t, err)
+	_, err = ApplyEffects(img, []Effect{{Type: EffectBorder, Amount: 0.1, Color: "red"}})
+	assert.Error(t, err)
+}
+
+func TestEffectJSON(t *testing.T) {
+	var effects []Effect
+	data := `[{"type":"padding","amount":0.1},{"type":"shadow","amount":0.04,"offsetY":0.02,"color":"#00000080"}]`
+	assert.NoError(t, json.Unmarshal([]byte(data), &effects))
+	assert.Equal(t, []Effect{
+		{Type: EffectPadding, Amount: 0.1},
+		{Type: EffectShadow, Amount: 0.04, OffsetY: 0.02, Color: "#00000080"},
+	}, effects)
+}
+
+func TestConvertImageEffects(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{
+		Sizes:     []image.Point{{32, 32}},
+		OutputDir: dir,
+		Formats:   []OutputFormat{FormatPNG},
+		Resize:    DefaultResizeOptions,
+		Effects:   []Effect{{Type: EffectCircle}},
+	}
+	result, err := ConvertImage(solidImage(100, color.White), opts)
+	assert.NoError(t, err)
+	if !assert.Len(t, result.Files, 1) {
+		return
+	}
+
+	f, err := os.Open(result.Files[0])
+	if err != nil {
+		t.Fatalf("Failed to open output: %v", err)
+	}
+	defer f.Close()
+	out, err := png.Decode(f)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 32, 32), out.Bounds())
+	_, _, _, a := out.At(0, 0).RGBA()
+	assert.Equal(t, uint32(0), a, "the circle mask is applied before resizing")
+}
@@ -0,0 +1,158 @@
+package image_convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FaviconIcoSizes are the sizes stored in favicon.ico.
+var FaviconIcoSizes = []image.Point{{16, 16}, {32, 32}, {48, 48}}
+
+// FaviconPNGs are the PNG files of the favicon bundle and their sizes.
+var FaviconPNGs = []struct {
+	Name string
+	Size int
+}{
+	{"favicon-16x16.png", 16},
+	{"favicon-32x32.png", 32},
+	{"apple-touch-icon.png", 180},
+	{"android-chrome-192x192.png", 192},
+	{"android-chrome-512x512.png", 512},
+}
+
+// Names of the generated text files of the favicon bundle.
+const (
+	FaviconManifestName = "site.webmanifest"
+	FaviconHTMLName     = "favicon.html"
+)
+
+// WebManifest is the subset of a web app manifest written with the favicons.
+type WebManifest struct {
+	Name            string            `json:"name"`
+	ShortName       string            `json:"short_name"`
+	Icons           []WebManifestIcon `json:"icons"`
+	ThemeColor      string            `json:"theme_color"`
+	BackgroundColor string            `json:"background_color"`
+	Display         string            `json:"display"`
+}
+
+// WebManifestIcon is an icon entry of a web app manifest.
+type WebManifestIcon struct {
+	Src   string `json:"src"`
+	Sizes string `json:"sizes"`
+	Type  string `json:"type"`
+}
+
+// GenerateFavicons writes favicon.ico, the FaviconPNGs, a site.webmanifest for the named
+// site and an HTML snippet with the <link> tags into outputDir. It returns the written paths.
+func GenerateFavicons(img image.Image, outputDir, name string, opts ResizeOptions) ([]string, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating directory: %w", err)
+	}
+
+	var paths []string
+
+	// favicon.ico
+	images := make([]image.Image, len(FaviconIcoSizes))
+	for i, size := range FaviconIcoSizes {
+		images[i] = Resize(img, size, opts)
+	}
+	icoPath := filepath.Join(outputDir, "favicon.ico")
+	icoFile, err := os.Create(icoPath)
+	if err != nil {
+		return paths, fmt.Errorf("error creating favicon.ico: %w", err)
+	}
+	if err := EncodeIco(icoFile, images); err != nil {
+		icoFile.Close()
+		return paths, fmt.Errorf("error encoding favicon.ico: %w", err)
+	}
+	if err := icoFile.Close(); err != nil {
+		return paths, err
+	}
+	paths = append(paths, icoPath)
+
+	// PNG icons
+	for _, icon := range FaviconPNGs {
+		path := filepath.Join(outputDir, icon.Name)
+		if err := savePNG(path, Resize(img, image.Pt(icon.Size, icon.Size), opts)); err != nil {
+			return paths, fmt.Errorf("error writing %s: %w", icon.Name, err)
+		}
+		paths = append(paths, path)
+	}
+
+	// Manifest and HTML snippet
+	manifest, err := json.MarshalIndent(FaviconManifest(name), "", "  ")
+	if err != nil {
+		return paths, err
+	}
+	manifestPath := filepath.Join(outputDir, FaviconManifestName)
+	if err := os.WriteFile(manifestPath, append(manifest, '\n'), 0644); err != nil {
+		return paths, fmt.Errorf("error writing %s: %w", FaviconManifestName, err)
+	}
+	paths = append(paths, manifestPath)
+
+	htmlPath := filepath.Join(outputDir, FaviconHTMLName)
+	if err := os.WriteFile(htmlPath, []byte(FaviconHTML()), 0644); err != nil {
+		return paths, fmt.Errorf("error writing %s: %w", FaviconHTMLName, err)
+	}
+	paths = append(paths, htmlPath)
+
+	return paths, nil
+}
+
+// FaviconManifest returns the web app manifest listing the android-chrome icons.
+func FaviconManifest(name string) WebManifest {
+	manifest := WebManifest{
+		Name:            name,
+		ShortName:       name,
+		ThemeColor:      "#ffffff",
+		BackgroundColor: "#ffffff",
+		Display:         "standalone",
+	}
+	for _, icon := range FaviconPNGs {
+		if strings.HasPrefix(icon.Name, "android-chrome-") {
+			manifest.Icons = append(manifest.Icons, WebManifestIcon{
+				Src:   "/" + icon.Name,
+				Sizes: fmt.Sprintf("%dx%d", icon.Size, icon.Size),
+				Type:  "image/png",
+			})
+		}
+	}
+	return manifest
+}
+
+// FaviconHTML returns the <link> tags to put in the <head> of a page using the favicon bundle.
+func FaviconHTML() string {
+	return `<link rel="icon" href="/favicon.ico" sizes="any">
+<link rel="icon" type="image/png" sizes="32x32" href="/favicon-32x32.png">
+<link rel="icon" type="image/png" sizes="16x16" href="/favicon-16x16.png">
+<link rel="apple-touch-icon" sizes="180x180" href="/apple-touch-icon.png">
+<link rel="manifest" href="/site.webmanifest">
+`
+}
+
+// ConvertImageToFavicons writes the favicon bundle into "./<basename>/favicon/".
+// The resize options default to DefaultResizeOptions.
+func ConvertImageToFavicons(inputPath string, opts ...ResizeOptions) error {
+	return convertTo(inputPath, nil, FormatFavicon, opts)
+}
+
+// savePNG writes img to path as a PNG with the best compression.
+func savePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(file, img); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,38 @@
+package image_convert
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateFavicons(t *testing.T) {
+	dir := t.TempDir()
+
+	paths, err := GenerateFavicons(testImage(64), dir, "Sterben", DefaultResizeOptions)
+	if err != nil {
+		t.Fatalf("Failed to generate favicons: %v", err)
+	}
+	assert.Len(t, paths, len(FaviconPNGs)+3)
+
+	for _, name := range []string{"favicon.ico", "apple-touch-icon.png", "android-chrome-512x512.png", "favicon-32x32.png"} {
+		assert.FileExists(t, filepath.Join(dir, name))
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, FaviconManifestName))
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	var manifest WebManifest
+	assert.NoError(t, json.Unmarshal(data, &manifest))
+	assert.Equal(t, "Sterben", manifest.Name)
+	assert.Len(t, manifest.Icons, 2)
+	assert.Equal(t, "512x512", manifest.Icons[1].Sizes)
+
+	html, err := os.ReadFile(filepath.Join(dir, FaviconHTMLName))
+	assert.NoError(t, err)
+	assert.Contains(t, string(html), `href="/site.webmanifest"`)
+}
@@ -0,0 +1,190 @@
+package image_convert
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+)
+
+// ResizeMode selects how an image is fitted into an output size with a different aspect ratio.
+type ResizeMode int
+
+const (
+	Contain ResizeMode = iota // Fit the whole image and pad the remaining space.
+	Cover                     // Fill the output and crop what doesn't fit.
+	Stretch                   // Scale each axis independently, distorting the image.
+)
+
+// ResizeModes lists every supported resize mode.
+var ResizeModes = []ResizeMode{Contain, Cover, Stretch}
+
+// String returns the name of the resize mode.
+func (m ResizeMode) String() string {
+	switch m {
+	case Cover:
+		return "cover"
+	case Stretch:
+		return "stretch"
+	default:
+		return "contain"
+	}
+}
+
+// Next returns the mode after m in ResizeModes, wrapping around.
+func (m ResizeMode) Next() ResizeMode {
+	return ResizeModes[(int(m)+1)%len(ResizeModes)]
+}
+
+// ParseResizeMode returns the resize mode with the given name.
+func ParseResizeMode(name string) (ResizeMode, error) {
+	for _, m := range ResizeModes {
+		if strings.EqualFold(name, m.String()) {
+			return m, nil
+		}
+	}
+	return Contain, fmt.Errorf("unknown resize mode %q", name)
+}
+
+// CropAnchor selects which part of the image is kept in Cover mode.
+type CropAnchor int
+
+const (
+	CropCenter CropAnchor = iota // Keep the center of the image.
+	CropSmart                    // Keep the part with the most detail.
+)
+
+// String returns the name of the crop anchor.
+func (a CropAnchor) String() string {
+	if a == CropSmart {
+		return "smart"
+	}
+	return "center"
+}
+
+// Next returns the other crop anchor.
+func (a CropAnchor) Next() CropAnchor {
+	if a == CropSmart {
+		return CropCenter
+	}
+	return CropSmart
+}
+
+// ParseColor parses a "#rgb", "#rrggbb" or "#rrggbbaa" color. An empty string or
+// "transparent" returns nil, which leaves the padding transparent.
+func ParseColor(s string) (color.Color, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.EqualFold(s, "transparent") {
+		return nil, nil
+	}
+
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	var c color.NRGBA
+	if len(hex) != 8 {
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+	if _, err := fmt.Sscanf(hex, "%02x%02x%02x%02x", &c.R, &c.G, &c.B, &c.A); err != nil {
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+	return c, nil
+}
+
+// FitBounds returns the part of img that is used and where it is drawn in an output of the given size.
+// In Contain mode the destination is centered and smaller than size on one axis, in Cover mode the
+// source is cropped, and in Stretch mode both rectangles are complete.
+func FitBounds(img image.Image, size image.Point, opts ResizeOptions) (src image.Rectangle, dst image.Rectangle) {
+	b := img.Bounds()
+	src, dst = FitRect(b.Size(), size, opts.Mode)
+	if opts.Mode == Cover && opts.Crop == CropSmart {
+		src = image.Rectangle{Max: src.Size()}.Add(smartCropOffset(img, src.Size()))
+	}
+	return src.Add(b.Min), dst
+}
+
+// FitRect is FitBounds for an image of the given size, with a centered crop in Cover mode.
+// The source rectangle is relative to the top left corner of the image.
+func FitRect(imgSize image.Point, size image.Point, mode ResizeMode) (src image.Rectangle, dst image.Rectangle) {
+	src = image.Rectangle{Max: imgSize}
+	dst = image.Rectangle{Max: size}
+	w, h := float64(imgSize.X), float64(imgSize.Y)
+	if w == 0 || h == 0 {
+		return src, dst
+	}
+
+	switch mode {
+	case Contain:
+		scale := math.Min(float64(size.X)/w, float64(size.Y)/h)
+		dw := max(1, int(math.Round(w*scale)))
+		dh := max(1, int(math.Round(h*scale)))
+		dst = image.Rect(0, 0, dw, dh).Add(image.Pt((size.X-dw)/2, (size.Y-dh)/2))
+
+	case Cover:
+		scale := math.Max(float64(size.X)/w, float64(size.Y)/h)
+		cw := min(imgSize.X, max(1, int(math.Round(float64(size.X)/scale))))
+		ch := min(imgSize.Y, max(1, int(math.Round(float64(size.Y)/scale))))
+		src = image.Rect(0, 0, cw, ch).Add(image.Pt((imgSize.X-cw)/2, (imgSize.Y-ch)/2))
+	}
+
+	return src, dst
+}
+
+// smartCropOffset returns the offset of the crop window of the given size which contains
+// the most edges. The window only slides along the axis the image is cropped on.
+func smartCropOffset(img image.Image, crop image.Point) image.Point {
+	b := img.Bounds()
+	horizontal := crop.X < b.Dx()
+	length := b.Dy()
+	window := crop.Y
+	if horizontal {
+		length = b.Dx()
+		window = crop.X
+	}
+	if window >= length {
+		return image.Point{}
+	}
+
+	// Sample the image on a grid to keep large images fast.
+	step := max(1, max(b.Dx(), b.Dy())/256)
+	energy := make([]float64, length)
+	luma := func(x, y int) float64 {
+		r, g, bl, a := img.At(x, y).RGBA()
+		return (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)) * float64(a) / 0xffff
+	}
+	for y := b.Min.Y; y < b.Max.Y-step; y += step {
+		for x := b.Min.X; x < b.Max.X-step; x += step {
+			l := luma(x, y)
+			e := math.Abs(l-luma(x+step, y)) + math.Abs(l-luma(x, y+step))
+			if horizontal {
+				energy[x-b.Min.X] += e
+			} else {
+				energy[y-b.Min.Y] += e
+			}
+		}
+	}
+
+	// Slide the window along the axis and keep the position with the most energy.
+	var sum float64
+	for i := 0; i < window; i++ {
+		sum += energy[i]
+	}
+	best, bestSum := 0, sum
+	for i := window; i < length; i++ {
+		sum += energy[i] - energy[i-window]
+		if sum > bestSum {
+			best, bestSum = i-window+1, sum
+		}
+	}
+
+	if horizontal {
+		return image.Pt(best, 0)
+	}
+	return image.Pt(0, best)
+}
@@ -0,0 +1,73 @@
+package image_convert
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFitRect(t *testing.T) {
+	wide := image.Pt(200, 100)
+	size := image.Pt(64, 64)
+
+	src, dst := FitRect(wide, size, Contain)
+	assert.Equal(t, image.Rect(0, 0, 200, 100), src)
+	assert.Equal(t, image.Rect(0, 16, 64, 48), dst)
+
+	src, dst = FitRect(wide, size, Cover)
+	assert.Equal(t, image.Rect(50, 0, 150, 100), src)
+	assert.Equal(t, image.Rect(0, 0, 64, 64), dst)
+
+	src, dst = FitRect(wide, size, Stretch)
+	assert.Equal(t, image.Rect(0, 0, 200, 100), src)
+	assert.Equal(t, image.Rect(0, 0, 64, 64), dst)
+}
+
+func TestFitBoundsSmartCrop(t *testing.T) {
+	// Flat image with a detailed area on the right.
+	img := image.NewRGBA(image.Rect(0, 0, 300, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 300; x++ {
+			c := color.RGBA{128, 128, 128, 255}
+			if x >= 200 && (x+y)%2 == 0 {
+				c = color.RGBA{255, 255, 255, 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	src, _ := FitBounds(img, image.Pt(32, 32), ResizeOptions{Mode: Cover, Crop: CropSmart})
+	assert.Equal(t, 100, src.Dx())
+	assert.GreaterOrEqual(t, src.Min.X, 190)
+}
+
+func TestResizeContainBackground(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+
+	dst := Resize(img, image.Pt(32, 32), ResizeOptions{Mode: Contain})
+	assert.Equal(t, uint8(0), dst.RGBAAt(16, 2).A, "padding is transparent")
+	assert.Equal(t, uint8(255), dst.RGBAAt(16, 16).A)
+
+	background, err := ParseColor("#f00")
+	assert.NoError(t, err)
+	dst = Resize(img, image.Pt(32, 32), ResizeOptions{Mode: Contain, Background: background})
+	assert.Equal(t, color.RGBA{255, 0, 0, 255}, dst.RGBAAt(16, 2))
+}
+
+func TestParseColor(t *testing.T) {
+	c, err := ParseColor("transparent")
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+
+	c, err = ParseColor("#11223380")
+	assert.NoError(t, err)
+	assert.Equal(t, color.NRGBA{0x11, 0x22, 0x33, 0x80}, c)
+
+	_, err = ParseColor("#12345")
+	assert.Error(t, err)
+}
@@ -0,0 +1,407 @@
+package image_convert
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/tiff"
+)
+
+// ImageFormat is a file format images can be converted to.
+type ImageFormat string
+
+const (
+	ImagePNG  ImageFormat = "png"
+	ImageJPEG ImageFormat = "jpeg"
+	ImageGIF  ImageFormat = "gif"
+	ImageBMP  ImageFormat = "bmp"
+	ImageTIFF ImageFormat = "tiff"
+)
+
+// ImageFormats lists every format images can be converted to.
+var ImageFormats = []ImageFormat{ImagePNG, ImageJPEG, ImageGIF, ImageBMP, ImageTIFF}
+
+// ParseImageFormat returns the image format with the given name or file extension, like "jpg" or ".tif".
+func ParseImageFormat(name string) (ImageFormat, error) {
+	name = strings.TrimPrefix(strings.ToLower(name), ".")
+	switch name {
+	case "jpg":
+		return ImageJPEG, nil
+	case "tif":
+		return ImageTIFF, nil
+	}
+	for _, f := range ImageFormats {
+		if name == string(f) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown image format %q", name)
+}
+
+// Extension returns the file extension of the format, with the leading dot.
+func (f ImageFormat) Extension() string {
+	if f == ImageJPEG {
+		return ".jpg"
+	}
+	return "." + string(f)
+}
+
+// Next returns the format after f in ImageFormats, wrapping around.
+func (f ImageFormat) Next() ImageFormat {
+	for i, format := range ImageFormats {
+		if format == f {
+			return ImageFormats[(i+1)%len(ImageFormats)]
+		}
+	}
+	return ImageFormats[0]
+}
+
+// GIFPalette is the palette GIF output is drawn with.
+type GIFPalette int
+
+const (
+	PaletteAdaptive GIFPalette = iota // Median cut of the image, with FormatOptions.Colors colors.
+	PalettePlan9                      // The 256 color Plan 9 palette.
+	PaletteWebSafe                    // The 216 web safe colors.
+)
+
+// GIFPalettes lists every GIF palette.
+var GIFPalettes = []GIFPalette{PaletteAdaptive, PalettePlan9, PaletteWebSafe}
+
+// String returns the name of the palette.
+func (p GIFPalette) String() string {
+	switch p {
+	case PalettePlan9:
+		return "plan9"
+	case PaletteWebSafe:
+		return "websafe"
+	default:
+		return "adaptive"
+	}
+}
+
+// Next returns the palette after p in GIFPalettes, wrapping around.
+func (p GIFPalette) Next() GIFPalette {
+	return GIFPalettes[(int(p)+1)%len(GIFPalettes)]
+}
+
+const (
+	DefaultJPEGQuality = 90
+	DefaultGIFColors   = 256
+)
+
+// FormatOptions configures ConvertFormat.
+type FormatOptions struct {
+	Format  ImageFormat
+	Quality int        // JPEG quality from 1 to 100, DefaultJPEGQuality if 0.
+	Colors  int        // Colors of an adaptive GIF palette from 2 to 256, DefaultGIFColors if 0.
+	Palette GIFPalette // Palette of GIF output.
+	Dither  bool       // Floyd-Steinberg dither GIF output.
+
+	// Width and Height resize the image, 0 keeps the original. With only one of them set,
+	// the other follows the aspect ratio, with both the image is fitted with Resize.
+	Width, Height int
+	Resize        ResizeOptions
+
+	// Background fills transparent pixels in formats without transparency, white if nil.
+	Background color.Color
+
+	// StripMetadata drops the EXIF, XMP, ICC and text metadata of the input. Without it, that
+	// metadata is kept when the input and output formats are both JPEG or both PNG.
+	StripMetadata bool
+}
+
+// ConvertFormat converts the image at inputPath to opts.Format and returns the written path.
+// An empty outputPath writes next to the input, with the extension of the format.
+func ConvertFormat(inputPath, outputPath string, opts FormatOptions) (string, error) {
+	expandedInputPath, err := expandPath(inputPath)
+	if err != nil {
+		return "", fmt.Errorf("error expanding input path: %w", err)
+	}
+	if outputPath == "" {
+		outputPath = FormatOutputPath(expandedInputPath, opts.Format)
+	}
+
+	data, err := os.ReadFile(expandedInputPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading image: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := ConvertFormatReader(bytes.NewReader(data), &buf, opts); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return "", fmt.Errorf("error creating directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
+		return "", fmt.Errorf("error writing image: %w", err)
+	}
+	return outputPath, nil
+}
+
+// FormatOutputPath returns the default output path of ConvertFormat, "<name>-converted<ext>"
+// when the input already has the extension of the format.
+func FormatOutputPath(inputPath string, format ImageFormat) string {
+	base := strings.TrimSuffix(inputPath, filepath.Ext(inputPath))
+	path := base + format.Extension()
+	if path == inputPath {
+		path = base + "-converted" + format.Extension()
+	}
+	return path
+}
+
+// ConvertFormatReader decodes an image from r and writes it to w in opts.Format.
+func ConvertFormatReader(r io.Reader, w io.Writer, opts FormatOptions) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	img, format, err := DecodeImage(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error decoding image: %w", err)
+	}
+
+	if opts.StripMetadata || ImageFormat(format) != opts.Format {
+		return EncodeImage(w, img, opts)
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeImage(&buf, img, opts); err != nil {
+		return err
+	}
+	out := buf.Bytes()
+	switch opts.Format {
+	case ImageJPEG:
+		out = insertJPEGSegments(out, jpegMetadata(data))
+	case ImagePNG:
+		out = insertPNGChunks(out, pngMetadata(data))
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// EncodeImage resizes img as configured and writes it to w in opts.Format, without metadata.
+func EncodeImage(w io.Writer, img image.Image, opts FormatOptions) error {
+	img = resizeTo(img, opts.Width, opts.Height, opts.Resize)
+
+	switch opts.Format {
+	case ImagePNG:
+		return png.Encode(w, img)
+
+	case ImageJPEG:
+		quality := opts.Quality
+		if quality == 0 {
+			quality = DefaultJPEGQuality
+		}
+		if quality < 1 || quality > 100 {
+			return fmt.Errorf("JPEG quality %d is outside 1 to 100", quality)
+		}
+		return jpeg.Encode(w, flatten(img, formatBackground(opts)), &jpeg.Options{Quality: quality})
+
+	case ImageGIF:
+		paletted, err := palettedImage(img, opts)
+		if err != nil {
+			return err
+		}
+		return gif.Encode(w, paletted, &gif.Options{NumColors: len(paletted.Palette)})
+
+	case ImageBMP:
+		return bmp.Encode(w, img)
+
+	case ImageTIFF:
+		return tiff.Encode(w, img, &tiff.Options{Compression: tiff.Deflate, Predictor: true})
+
+	default:
+		return fmt.Errorf("unknown image format %q", opts.Format)
+	}
+}
+
+// formatBackground returns the color transparent pixels are filled with.
+func formatBackground(opts FormatOptions) color.Color {
+	if opts.Background == nil {
+		return color.White
+	}
+	return opts.Background
+}
+
+// resizeTo resizes img to width and height, computing a missing one from the aspect ratio.
+// It returns img unchanged if both are 0.
+func resizeTo(img image.Image, width, height int, opts ResizeOptions) image.Image {
+	if width <= 0 && height <= 0 {
+		return img
+	}
+
+	b := img.Bounds()
+	switch {
+	case width <= 0:
+		width = max(1, (b.Dx()*height+b.Dy()/2)/b.Dy())
+	case height <= 0:
+		height = max(1, (b.Dy()*width+b.Dx()/2)/b.Dx())
+	}
+	return Resize(img, image.Pt(width, height), opts)
+}
+
+// palettedImage draws img with the GIF palette of opts. Adaptive palettes get a transparent
+// color when img has transparent pixels.
+func palettedImage(img image.Image, opts FormatOptions) (*image.Paletted, error) {
+	var p color.Palette
+	switch opts.Palette {
+	case PalettePlan9:
+		p = palette.Plan9
+	case PaletteWebSafe:
+		p = palette.WebSafe
+	default:
+		n := opts.Colors
+		if n == 0 {
+			n = DefaultGIFColors
+		}
+		if n < 2 || n > 256 {
+			return nil, fmt.Errorf("GIF colors %d is outside 2 to 256", n)
+		}
+		if !isOpaque(img) {
+			p = append(p, color.NRGBA{})
+			n--
+		}
+		for _, c := range medianCut(img, n) {
+			p = append(p, c.Color)
+		}
+		if len(p) == 0 {
+			p = append(p, color.NRGBA{A: 255})
+		}
+	}
+
+	b := img.Bounds()
+	paletted := image.NewPaletted(image.Rect(0, 0, b.Dx(), b.Dy()), p)
+	var drawer draw.Drawer = draw.Src
+	if opts.Dither {
+		drawer = draw.FloydSteinberg
+	}
+	drawer.Draw(paletted, paletted.Bounds(), img, b.Min)
+	return paletted, nil
+}
+
+// isOpaque reports whether every pixel of img is fully opaque.
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0xffff {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// jpegMetadata returns the EXIF, XMP, ICC and IPTC segments of a JPEG, with their markers.
+// The EXIF orientation is reset, as DecodeImage already applied it to the pixels.
+func jpegMetadata(data []byte) [][]byte {
+	if len(data) < 4 || data[0] != 0xff || data[1] != 0xd8 {
+		return nil
+	}
+
+	var segments [][]byte
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xff {
+			break
+		}
+		marker := data[i+1]
+		if marker == 0xda || marker == 0xd9 { // Start of scan or end of image.
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			break
+		}
+
+		// APP1 holds EXIF and XMP, APP2 ICC profiles and APP13 IPTC.
+		if marker == 0xe1 || marker == 0xe2 || marker == 0xed {
+			segment := append([]byte(nil), data[i:end]...)
+			if marker == 0xe1 && bytes.HasPrefix(segment[4:], []byte("Exif\x00\x00")) {
+				tiff := segment[10:]
+				if order, offset := exifOrientationOffset(tiff); offset >= 0 {
+					order.PutUint16(tiff[offset:], 1)
+				}
+			}
+			segments = append(segments, segment)
+		}
+		i = end
+	}
+	return segments
+}
+
+// insertJPEGSegments returns the JPEG in data with segments inserted after its start of image marker.
+func insertJPEGSegments(data []byte, segments [][]byte) []byte {
+	if len(segments) == 0 || len(data) < 2 {
+		return data
+	}
+	out := append([]byte(nil), data[:2]...)
+	for _, segment := range segments {
+		out = append(out, segment...)
+	}
+	return append(out, data[2:]...)
+}
+
+// pngSignature starts every PNG file.
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+// pngMetadataChunks are the chunk types kept from the input PNG.
+var pngMetadataChunks = map[string]bool{
+	"tEXt": true, "zTXt": true, "iTXt": true, "eXIf": true, "iCCP": true, "sRGB": true, "gAMA": true, "pHYs": true,
+}
+
+// pngMetadata returns the metadata chunks of a PNG, with their length, type and CRC.
+func pngMetadata(data []byte) [][]byte {
+	if !bytes.HasPrefix(data, []byte(pngSignature)) {
+		return nil
+	}
+
+	var chunks [][]byte
+	for i := len(pngSignature); i+8 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			break
+		}
+		typ := string(data[i+4 : i+8])
+		if typ == "IEND" {
+			break
+		}
+		if pngMetadataChunks[typ] {
+			chunks = append(chunks, append([]byte(nil), data[i:end]...))
+		}
+		i = end
+	}
+	return chunks
+}
+
+// insertPNGChunks returns the PNG in data with chunks inserted after its IHDR chunk.
+func insertPNGChunks(data []byte, chunks [][]byte) []byte {
+	ihdrEnd := len(pngSignature) + 12 + 13
+	if len(chunks) == 0 || len(data) < ihdrEnd {
+		return data
+	}
+	out := append([]byte(nil), data[:ihdrEnd]...)
+	for _, chunk := range chunks {
+		out = append(out, chunk...)
+	}
+	return append(out, data[ihdrEnd:]...)
+}
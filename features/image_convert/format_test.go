@@ -0,0 +1,146 @@
+package image_convert
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseImageFormat(t *testing.T) {
+	for name, want := range map[string]ImageFormat{"png": ImagePNG, "JPG": ImageJPEG, ".jpeg": ImageJPEG, "tif": ImageTIFF, "bmp": ImageBMP} {
+		got, err := ParseImageFormat(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+	_, err := ParseImageFormat("webp")
+	assert.Error(t, err, "webp can only be decoded")
+
+	assert.Equal(t, ".jpg", ImageJPEG.Extension())
+	assert.Equal(t, ImagePNG, ImageTIFF.Next())
+}
+
+func TestEncodeImageFormats(t *testing.T) {
+	img := testImage(16)
+	for _, format := range ImageFormats {
+		var buf bytes.Buffer
+		if !assert.NoError(t, EncodeImage(&buf, img, FormatOptions{Format: format}), format) {
+			continue
+		}
+		decoded, name, err := DecodeImage(&buf)
+		assert.NoError(t, err, format)
+		assert.Equal(t, string(format), name)
+		assert.Equal(t, image.Rect(0, 0, 16, 16), decoded.Bounds(), format)
+	}
+
+	// JPEG has no transparency, so the left half is filled with the background.
+	var buf bytes.Buffer
+	assert.NoError(t, EncodeImage(&buf, img, FormatOptions{Format: ImageJPEG, Background: color.Black}))
+	decoded, err := jpeg.Decode(&buf)
+	assert.NoError(t, err)
+	r, g, b, _ := decoded.At(2, 8).RGBA()
+	assert.Less(t, r+g+b, uint32(0x1000))
+
+	assert.Error(t, EncodeImage(&buf, img, FormatOptions{Format: ImageJPEG, Quality: 101}))
+	assert.Error(t, EncodeImage(&buf, img, FormatOptions{Format: ImageGIF, Colors: 1}))
+	assert.Error(t, EncodeImage(&buf, img, FormatOptions{Format: "webp"}))
+}
+
+func TestEncodeImageResize(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	var buf bytes.Buffer
+	assert.NoError(t, EncodeImage(&buf, img, FormatOptions{Format: ImagePNG, Width: 10}))
+	cfg, err := png.DecodeConfig(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, cfg.Width)
+	assert.Equal(t, 5, cfg.Height, "the height follows the aspect ratio")
+}
+
+func TestEncodeImageGIFPalette(t *testing.T) {
+	img := testImage(16)
+	var buf bytes.Buffer
+	assert.NoError(t, EncodeImage(&buf, img, FormatOptions{Format: ImageGIF, Colors: 4, Dither: true}))
+	decoded, err := gif.Decode(&buf)
+	assert.NoError(t, err)
+
+	p := decoded.(*image.Paletted)
+	assert.LessOrEqual(t, len(p.Palette), 4)
+	assert.Equal(t, color.NRGBA{}, color.NRGBAModel.Convert(p.At(0, 0)), "transparency gets its own color")
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, color.NRGBAModel.Convert(p.At(15, 15)))
+
+	buf.Reset()
+	assert.NoError(t, EncodeImage(&buf, img, FormatOptions{Format: ImageGIF, Palette: PaletteWebSafe}))
+	decoded, err = gif.Decode(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, color.NRGBAModel.Convert(decoded.At(15, 15)))
+}
+
+func TestConvertFormatMetadata(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 4)), nil))
+	input := withOrientation(buf.Bytes(), 6)
+
+	// The EXIF block is kept, with the orientation reset as the pixels are already rotated.
+	var out bytes.Buffer
+	assert.NoError(t, ConvertFormatReader(bytes.NewReader(input), &out, FormatOptions{Format: ImageJPEG}))
+	assert.Len(t, jpegMetadata(out.Bytes()), 1)
+	assert.Equal(t, 1, jpegOrientation(out.Bytes()))
+	decoded, _, err := DecodeImage(&out)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 4, 8), decoded.Bounds())
+
+	out.Reset()
+	assert.NoError(t, ConvertFormatReader(bytes.NewReader(input), &out, FormatOptions{Format: ImageJPEG, StripMetadata: true}))
+	assert.Empty(t, jpegMetadata(out.Bytes()))
+}
+
+func pngChunk(typ string, data []byte) []byte {
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	chunk = append(chunk, typ...)
+	chunk = append(chunk, data...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+func TestConvertFormatPNGMetadata(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, testImage(4)))
+	text := pngChunk("tEXt", []byte("Author\x00me"))
+	input := insertPNGChunks(buf.Bytes(), [][]byte{text})
+
+	var out bytes.Buffer
+	assert.NoError(t, ConvertFormatReader(bytes.NewReader(input), &out, FormatOptions{Format: ImagePNG}))
+	assert.Equal(t, [][]byte{text}, pngMetadata(out.Bytes()))
+
+	out.Reset()
+	assert.NoError(t, ConvertFormatReader(bytes.NewReader(input), &out, FormatOptions{Format: ImagePNG, StripMetadata: true}))
+	assert.Empty(t, pngMetadata(out.Bytes()))
+}
+
+func TestConvertFormat(t *testing.T) {
+	dir := t.TempDir()
+	input := writeTestPNG(t, dir, "photo.png", 32)
+
+	path, err := ConvertFormat(input, "", FormatOptions{Format: ImageJPEG, Quality: 80})
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "photo.jpg"), path)
+
+	path, err = ConvertFormat(input, "", FormatOptions{Format: ImagePNG})
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "photo-converted.png"), path, "the input is never overwritten by default")
+
+	f, err := os.Open(path)
+	assert.NoError(t, err)
+	defer f.Close()
+	_, format, err := image.DecodeConfig(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "png", format)
+}
@@ -0,0 +1,112 @@
+package image_convert
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+)
+
+// IcnsType is a PNG-based icon type of an ICNS file.
+type IcnsType struct {
+	Code string // Four character OSType.
+	Size int    // Width and height in pixels.
+}
+
+// IcnsTypes are the standard PNG-based icon types, @2x variants included.
+var IcnsTypes = []IcnsType{
+	{"ic11", 32},   // 16x16@2x
+	{"ic12", 64},   // 32x32@2x
+	{"ic07", 128},  // 128x128
+	{"ic13", 256},  // 128x128@2x
+	{"ic08", 256},  // 256x256
+	{"ic14", 512},  // 256x256@2x
+	{"ic09", 512},  // 512x512
+	{"ic10", 1024}, // 512x512@2x
+}
+
+// icnsHeaderSize is the size of the file header and of each element header.
+const icnsHeaderSize = 8
+
+// IcnsElement is an icon stored in an ICNS file.
+type IcnsElement struct {
+	Code string
+	Data []byte
+}
+
+// EncodeIcns writes img as an ICNS file with every type in IcnsTypes, resized with opts.
+func EncodeIcns(w io.Writer, img image.Image, opts ResizeOptions) error {
+	// Resize each distinct size once, some types share a size.
+	resized := make(map[int][]byte)
+	elements := make([]IcnsElement, 0, len(IcnsTypes))
+	for _, t := range IcnsTypes {
+		data, ok := resized[t.Size]
+		if !ok {
+			var err error
+			data, err = encodePngEntry(Resize(img, image.Pt(t.Size, t.Size), opts))
+			if err != nil {
+				return fmt.Errorf("error encoding %s: %w", t.Code, err)
+			}
+			resized[t.Size] = data
+		}
+		elements = append(elements, IcnsElement{Code: t.Code, Data: data})
+	}
+
+	return WriteIcns(w, elements)
+}
+
+// WriteIcns writes the elements as an ICNS container.
+func WriteIcns(w io.Writer, elements []IcnsElement) error {
+	total := icnsHeaderSize
+	for _, e := range elements {
+		if len(e.Code) != 4 {
+			return fmt.Errorf("invalid icon type %q", e.Code)
+		}
+		total += icnsHeaderSize + len(e.Data)
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(total)
+	buf.WriteString("icns")
+	binary.Write(&buf, binary.BigEndian, uint32(total))
+	for _, e := range elements {
+		buf.WriteString(e.Code)
+		binary.Write(&buf, binary.BigEndian, uint32(icnsHeaderSize+len(e.Data)))
+		buf.Write(e.Data)
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// ReadIcns parses an ICNS container and returns its elements.
+func ReadIcns(r io.Reader) ([]IcnsElement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < icnsHeaderSize || string(data[:4]) != "icns" {
+		return nil, errors.New("not an ICNS file")
+	}
+	if int(binary.BigEndian.Uint32(data[4:])) != len(data) {
+		return nil, errors.New("ICNS file size does not match its header")
+	}
+
+	var elements []IcnsElement
+	for offset := icnsHeaderSize; offset < len(data); {
+		if offset+icnsHeaderSize > len(data) {
+			return nil, errors.New("truncated ICNS element header")
+		}
+		code := string(data[offset : offset+4])
+		length := int(binary.BigEndian.Uint32(data[offset+4:]))
+		if length < icnsHeaderSize || offset+length > len(data) {
+			return nil, fmt.Errorf("invalid length for ICNS element %q", code)
+		}
+		elements = append(elements, IcnsElement{Code: code, Data: data[offset+icnsHeaderSize : offset+length]})
+		offset += length
+	}
+
+	return elements, nil
+}
@@ -0,0 +1,42 @@
+package image_convert
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeIcnsRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeIcns(&buf, testImage(300), DefaultResizeOptions); err != nil {
+		t.Fatalf("Failed to encode ICNS: %v", err)
+	}
+
+	elements, err := ReadIcns(&buf)
+	if err != nil {
+		t.Fatalf("Failed to read ICNS: %v", err)
+	}
+	assert.Len(t, elements, len(IcnsTypes))
+
+	for i, element := range elements {
+		assert.Equal(t, IcnsTypes[i].Code, element.Code)
+
+		img, err := png.Decode(bytes.NewReader(element.Data))
+		if err != nil {
+			t.Fatalf("Failed to decode %s: %v", element.Code, err)
+		}
+		size := IcnsTypes[i].Size
+		assert.Equal(t, image.Pt(size, size), img.Bounds().Size(), element.Code)
+	}
+}
+
+func TestReadIcnsInvalid(t *testing.T) {
+	_, err := ReadIcns(bytes.NewReader([]byte("icns\x00\x00\x00\x10ic07\x00\x00\x00\xff")))
+	assert.Error(t, err)
+
+	_, err = ReadIcns(bytes.NewReader([]byte("nope")))
+	assert.Error(t, err)
+}
@@ -0,0 +1,403 @@
+package image_convert
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Resource types stored in the ICONDIR header.
+const (
+	icoTypeIcon   uint16 = 1
+	icoTypeCursor uint16 = 2
+)
+
+const (
+	icoDirSize      = 6  // ICONDIR header size.
+	icoDirEntrySize = 16 // ICONDIRENTRY size.
+	bmpInfoSize     = 40 // BITMAPINFOHEADER size.
+
+	// pngEntryMinSize is the smallest entry size stored as PNG rather than BMP.
+	pngEntryMinSize = 256
+
+	// maxIcoSize is the largest width or height an ICO entry can have.
+	maxIcoSize = 256
+)
+
+// icoEntry is a single image of an ICO or CUR file.
+// For icons planes and bitCount are the color planes and bits per pixel,
+// for cursors they hold the X and Y hotspot.
+type icoEntry struct {
+	img      image.Image
+	planes   uint16
+	bitCount uint16
+}
+
+// EncodeIco writes images as a single ICO file containing one entry per image.
+// Entries of 256px are PNG-compressed, smaller entries are stored as 32-bit BMP/DIB.
+func EncodeIco(w io.Writer, images []image.Image) error {
+	entries := make([]icoEntry, len(images))
+	for i, img := range images {
+		entries[i] = icoEntry{img: img, planes: 1, bitCount: 32}
+	}
+	return encodeIconDir(w, icoTypeIcon, entries)
+}
+
+// encodeIconDir writes the ICONDIR header, the directory entries and the image data.
+func encodeIconDir(w io.Writer, typ uint16, entries []icoEntry) error {
+	if len(entries) == 0 {
+		return errors.New("no images to encode")
+	}
+	if len(entries) > 0xffff {
+		return fmt.Errorf("too many images: %d", len(entries))
+	}
+
+	// Encode the image data first, the directory needs their sizes.
+	data := make([][]byte, len(entries))
+	for i, entry := range entries {
+		size := entry.img.Bounds().Size()
+		if size.X <= 0 || size.Y <= 0 || size.X > maxIcoSize || size.Y > maxIcoSize {
+			return fmt.Errorf("invalid icon size %dx%d, must be between 1 and %d", size.X, size.Y, maxIcoSize)
+		}
+
+		var err error
+		if size.X >= pngEntryMinSize || size.Y >= pngEntryMinSize {
+			data[i], err = encodePngEntry(entry.img)
+		} else {
+			data[i], err = encodeBmpEntry(entry.img)
+		}
+		if err != nil {
+			return fmt.Errorf("error encoding %dx%d entry: %w", size.X, size.Y, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, [3]uint16{0, typ, uint16(len(entries))})
+
+	offset := icoDirSize + icoDirEntrySize*len(entries)
+	for i, entry := range entries {
+		size := entry.img.Bounds().Size()
+		buf.WriteByte(icoDimension(size.X))
+		buf.WriteByte(icoDimension(size.Y))
+		buf.WriteByte(0) // Color count, 0 for images without a palette.
+		buf.WriteByte(0) // Reserved.
+		binary.Write(&buf, binary.LittleEndian, entry.planes)
+		binary.Write(&buf, binary.LittleEndian, entry.bitCount)
+		binary.Write(&buf, binary.LittleEndian, uint32(len(data[i])))
+		binary.Write(&buf, binary.LittleEndian, uint32(offset))
+		offset += len(data[i])
+	}
+
+	for _, d := range data {
+		buf.Write(d)
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// icoDimension returns the directory value for a width or height, where 0 means 256.
+func icoDimension(n int) byte {
+	if n >= maxIcoSize {
+		return 0
+	}
+	return byte(n)
+}
+
+// encodePngEntry encodes img as PNG with the best compression.
+func encodePngEntry(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, convertToRGBA(img)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeBmpEntry encodes img as a 32-bit BMP/DIB without the file header, as stored in ICO files.
+// The pixel rows are followed by the 1-bit AND mask, which marks fully transparent pixels.
+func encodeBmpEntry(img image.Image) ([]byte, error) {
+	rgba := convertToRGBA(img)
+	size := rgba.Bounds().Size()
+
+	maskStride := ((size.X + 31) / 32) * 4
+	pixelSize := size.X * size.Y * 4
+	maskSize := maskStride * size.Y
+
+	var buf bytes.Buffer
+	buf.Grow(bmpInfoSize + pixelSize + maskSize)
+
+	// BITMAPINFOHEADER, the height covers both the pixels and the mask.
+	binary.Write(&buf, binary.LittleEndian, struct {
+		Size          uint32
+		Width         int32
+		Height        int32
+		Planes        uint16
+		BitCount      uint16
+		Compression   uint32
+		SizeImage     uint32
+		XPelsPerMeter int32
+		YPelsPerMeter int32
+		ClrUsed       uint32
+		ClrImportant  uint32
+	}{
+		Size:      bmpInfoSize,
+		Width:     int32(size.X),
+		Height:    int32(size.Y * 2),
+		Planes:    1,
+		BitCount:  32,
+		SizeImage: uint32(pixelSize + maskSize),
+	})
+
+	// Pixels are stored bottom-up as non-premultiplied BGRA.
+	mask := make([]byte, maskSize)
+	for y := size.Y - 1; y >= 0; y-- {
+		row := size.Y - 1 - y
+		for x := 0; x < size.X; x++ {
+			c := nrgbaAt(rgba, x, y)
+			buf.Write([]byte{c.B, c.G, c.R, c.A})
+			if c.A == 0 {
+				mask[row*maskStride+x/8] |= 0x80 >> (x % 8)
+			}
+		}
+	}
+	buf.Write(mask)
+
+	return buf.Bytes(), nil
+}
+
+// IcoEntry describes an image stored in an ICO or CUR file.
+type IcoEntry struct {
+	Width    int
+	Height   int
+	BitCount int         // Bits per pixel of the stored image.
+	PNG      bool        // Stored as PNG rather than BMP/DIB.
+	Hotspot  image.Point // Cursor hotspot, only set for CUR files.
+	DataSize int         // Size of the stored image in bytes.
+	Image    image.Image // Decoded image.
+}
+
+// IcoFile is a decoded ICO or CUR file.
+type IcoFile struct {
+	Cursor  bool
+	Entries []IcoEntry
+}
+
+// DecodeIco decodes every entry of an ICO or CUR file.
+func DecodeIco(r io.Reader) (*IcoFile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < icoDirSize || binary.LittleEndian.Uint16(data) != 0 {
+		return nil, errors.New("not an ICO or CUR file")
+	}
+
+	typ := binary.LittleEndian.Uint16(data[2:])
+	if typ != icoTypeIcon && typ != icoTypeCursor {
+		return nil, fmt.Errorf("unknown ICO resource type %d", typ)
+	}
+	count := int(binary.LittleEndian.Uint16(data[4:]))
+	if len(data) < icoDirSize+count*icoDirEntrySize {
+		return nil, errors.New("truncated ICO directory")
+	}
+
+	file := &IcoFile{Cursor: typ == icoTypeCursor}
+	for i := 0; i < count; i++ {
+		dir := data[icoDirSize+i*icoDirEntrySize:]
+		size := int(binary.LittleEndian.Uint32(dir[8:]))
+		offset := int(binary.LittleEndian.Uint32(dir[12:]))
+		if size <= 0 || offset < 0 || offset+size > len(data) {
+			return nil, fmt.Errorf("entry %d is out of bounds", i)
+		}
+
+		entry := IcoEntry{DataSize: size}
+		if file.Cursor {
+			entry.Hotspot = image.Pt(int(binary.LittleEndian.Uint16(dir[4:])), int(binary.LittleEndian.Uint16(dir[6:])))
+		}
+
+		d := data[offset : offset+size]
+		if bytes.HasPrefix(d, []byte("\x89PNG\r\n\x1a\n")) {
+			entry.PNG = true
+			entry.Image, err = png.Decode(bytes.NewReader(d))
+			if err != nil {
+				return nil, fmt.Errorf("error decoding entry %d: %w", i, err)
+			}
+			entry.BitCount = pngBitCount(d)
+		} else {
+			entry.Image, entry.BitCount, err = decodeBmpEntry(d)
+			if err != nil {
+				return nil, fmt.Errorf("error decoding entry %d: %w", i, err)
+			}
+		}
+
+		b := entry.Image.Bounds()
+		entry.Width, entry.Height = b.Dx(), b.Dy()
+		file.Entries = append(file.Entries, entry)
+	}
+
+	return file, nil
+}
+
+// pngBitCount returns the bits per pixel of a PNG from its IHDR chunk.
+func pngBitCount(data []byte) int {
+	if len(data) < 26 {
+		return 0
+	}
+	depth := int(data[24])
+	switch data[25] { // Color type.
+	case 2:
+		return depth * 3
+	case 4:
+		return depth * 2
+	case 6:
+		return depth * 4
+	default:
+		return depth
+	}
+}
+
+// decodeBmpEntry decodes a BMP/DIB entry of an ICO file with 1, 4, 8, 24 or 32 bits per pixel.
+// The AND mask is applied as transparency, except for 32-bit images that have an alpha channel.
+func decodeBmpEntry(data []byte) (image.Image, int, error) {
+	if len(data) < bmpInfoSize {
+		return nil, 0, errors.New("truncated BMP header")
+	}
+	headerSize := int(binary.LittleEndian.Uint32(data))
+	width := int(int32(binary.LittleEndian.Uint32(data[4:])))
+	height := int(int32(binary.LittleEndian.Uint32(data[8:]))) / 2
+	bitCount := int(binary.LittleEndian.Uint16(data[14:]))
+	compression := binary.LittleEndian.Uint32(data[16:])
+	colorsUsed := int(binary.LittleEndian.Uint32(data[32:]))
+
+	if width <= 0 || height <= 0 || width > maxIcoSize || height > maxIcoSize {
+		return nil, 0, fmt.Errorf("invalid BMP size %dx%d", width, height)
+	}
+	if compression != 0 {
+		return nil, 0, fmt.Errorf("unsupported BMP compression %d", compression)
+	}
+
+	// Palette for indexed images.
+	var palette []color.NRGBA
+	offset := headerSize
+	if bitCount <= 8 {
+		if colorsUsed == 0 {
+			colorsUsed = 1 << bitCount
+		}
+		if offset+colorsUsed*4 > len(data) {
+			return nil, 0, errors.New("truncated BMP palette")
+		}
+		for i := 0; i < colorsUsed; i++ {
+			c := data[offset+i*4:]
+			palette = append(palette, color.NRGBA{R: c[2], G: c[1], B: c[0], A: 255})
+		}
+		offset += colorsUsed * 4
+	}
+
+	switch bitCount {
+	case 1, 4, 8, 24, 32:
+	default:
+		return nil, 0, fmt.Errorf("unsupported BMP bit count %d", bitCount)
+	}
+
+	stride := ((width*bitCount + 31) / 32) * 4
+	maskStride := ((width + 31) / 32) * 4
+	maskOffset := offset + stride*height
+	if maskOffset > len(data) {
+		return nil, 0, errors.New("truncated BMP pixels")
+	}
+	hasMask := maskOffset+maskStride*height <= len(data)
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	hasAlpha := false
+	for row := 0; row < height; row++ {
+		y := height - 1 - row // Rows are stored bottom-up.
+		line := data[offset+row*stride:]
+		for x := 0; x < width; x++ {
+			var c color.NRGBA
+			switch bitCount {
+			case 32:
+				c = color.NRGBA{R: line[x*4+2], G: line[x*4+1], B: line[x*4], A: line[x*4+3]}
+				hasAlpha = hasAlpha || c.A != 0
+			case 24:
+				c = color.NRGBA{R: line[x*3+2], G: line[x*3+1], B: line[x*3], A: 255}
+			default:
+				perByte := 8 / bitCount
+				shift := uint(8 - bitCount*(x%perByte+1))
+				index := int(line[x/perByte]>>shift) & (1<<bitCount - 1)
+				if index < len(palette) {
+					c = palette[index]
+				}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	// Apply the AND mask, 32-bit images without any alpha rely on it too.
+	if hasMask && (bitCount != 32 || !hasAlpha) {
+		for row := 0; row < height; row++ {
+			y := height - 1 - row
+			mask := data[maskOffset+row*maskStride:]
+			for x := 0; x < width; x++ {
+				c := img.NRGBAAt(x, y)
+				if mask[x/8]&(0x80>>(x%8)) != 0 {
+					c.A = 0
+				} else {
+					c.A = 255
+				}
+				img.SetNRGBA(x, y, c)
+			}
+		}
+	}
+
+	return img, bitCount, nil
+}
+
+// ExtractIco decodes the ICO or CUR file at path and writes each entry as
+// "<name>-<width>x<height>-<bits>bpp.png" into outputDir. It returns the written paths.
+func ExtractIco(path, outputDir string) ([]string, error) {
+	expandedPath, err := expandPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(expandedPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	file, err := DecodeIco(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSuffix(filepath.Base(expandedPath), filepath.Ext(expandedPath))
+	var paths []string
+	for i, entry := range file.Entries {
+		outputPath := filepath.Join(outputDir, fmt.Sprintf("%s-%dx%d-%dbpp.png", name, entry.Width, entry.Height, entry.BitCount))
+		for _, existing := range paths {
+			if existing == outputPath {
+				outputPath = filepath.Join(outputDir, fmt.Sprintf("%s-%dx%d-%dbpp-%d.png", name, entry.Width, entry.Height, entry.BitCount, i))
+			}
+		}
+		if err := savePNG(outputPath, entry.Image); err != nil {
+			return paths, fmt.Errorf("error writing %s: %w", outputPath, err)
+		}
+		paths = append(paths, outputPath)
+	}
+
+	return paths, nil
+}
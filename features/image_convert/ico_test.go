@@ -0,0 +1,135 @@
+package image_convert
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testImage(size int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := size / 2; x < size; x++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	return img
+}
+
+func TestEncodeIco(t *testing.T) {
+	var buf bytes.Buffer
+	images := []image.Image{testImage(16), testImage(32), testImage(256)}
+	if err := EncodeIco(&buf, images); err != nil {
+		t.Fatalf("Failed to encode ICO: %v", err)
+	}
+	data := buf.Bytes()
+
+	assert.Equal(t, uint16(1), binary.LittleEndian.Uint16(data[2:]))
+	assert.Equal(t, uint16(3), binary.LittleEndian.Uint16(data[4:]))
+
+	entry := func(i int) []byte { return data[icoDirSize+i*icoDirEntrySize:] }
+	offset := func(i int) uint32 { return binary.LittleEndian.Uint32(entry(i)[12:]) }
+
+	assert.Equal(t, byte(16), entry(0)[0])
+	assert.Equal(t, byte(32), entry(1)[0])
+	assert.Equal(t, byte(0), entry(2)[0], "256px entries are stored as 0")
+
+	// Small sizes are BMP with a doubled height for the AND mask.
+	bmp := data[offset(0):]
+	assert.Equal(t, uint32(bmpInfoSize), binary.LittleEndian.Uint32(bmp))
+	assert.Equal(t, int32(32), int32(binary.LittleEndian.Uint32(bmp[8:])))
+
+	// The left half is transparent, so the first mask byte of a 16px row is all ones.
+	mask := bmp[bmpInfoSize+16*16*4:]
+	assert.Equal(t, byte(0xff), mask[0])
+	assert.Equal(t, byte(0x00), mask[1])
+
+	// The 256px entry is PNG.
+	assert.Equal(t, []byte("\x89PNG"), data[offset(2):offset(2)+4])
+	assert.Equal(t, len(data), int(offset(2)+binary.LittleEndian.Uint32(entry(2)[8:])))
+}
+
+func TestEncodeIcoInvalidSize(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, EncodeIco(&buf, []image.Image{testImage(512)}))
+	assert.Error(t, EncodeIco(&buf, nil))
+}
+
+func TestDecodeIcoRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeIco(&buf, []image.Image{testImage(16), testImage(256)}); err != nil {
+		t.Fatalf("Failed to encode ICO: %v", err)
+	}
+
+	file, err := DecodeIco(&buf)
+	if err != nil {
+		t.Fatalf("Failed to decode ICO: %v", err)
+	}
+	assert.False(t, file.Cursor)
+	assert.Len(t, file.Entries, 2)
+
+	small := file.Entries[0]
+	assert.Equal(t, 16, small.Width)
+	assert.Equal(t, 32, small.BitCount)
+	assert.False(t, small.PNG)
+	assert.Equal(t, color.NRGBA{}, color.NRGBAModel.Convert(small.Image.At(0, 0)))
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, color.NRGBAModel.Convert(small.Image.At(15, 15)))
+
+	large := file.Entries[1]
+	assert.Equal(t, 256, large.Width)
+	assert.True(t, large.PNG)
+	assert.Equal(t, 32, large.BitCount)
+}
+
+func TestDecodeIcoIndexed(t *testing.T) {
+	// A 2x2 1-bit icon: white/black on the top row, with the bottom row masked out.
+	var bmp bytes.Buffer
+	binary.Write(&bmp, binary.LittleEndian, []uint32{bmpInfoSize, 2, 4})
+	binary.Write(&bmp, binary.LittleEndian, []uint16{1, 1})
+	binary.Write(&bmp, binary.LittleEndian, []uint32{0, 0, 0, 0, 2, 0})
+	bmp.Write([]byte{0, 0, 0, 0, 255, 255, 255, 0}) // Palette: black, white.
+	bmp.Write([]byte{0, 0, 0, 0, 0x80, 0, 0, 0})    // Pixels, bottom-up.
+	bmp.Write([]byte{0xc0, 0, 0, 0, 0, 0, 0, 0})    // AND mask, bottom-up.
+
+	var ico bytes.Buffer
+	binary.Write(&ico, binary.LittleEndian, []uint16{0, 1, 1})
+	ico.Write([]byte{2, 2, 2, 0})
+	binary.Write(&ico, binary.LittleEndian, []uint16{1, 1})
+	binary.Write(&ico, binary.LittleEndian, []uint32{uint32(bmp.Len()), icoDirSize + icoDirEntrySize})
+	ico.Write(bmp.Bytes())
+
+	file, err := DecodeIco(&ico)
+	if err != nil {
+		t.Fatalf("Failed to decode ICO: %v", err)
+	}
+	entry := file.Entries[0]
+	assert.Equal(t, 1, entry.BitCount)
+	assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, color.NRGBAModel.Convert(entry.Image.At(0, 0)))
+	assert.Equal(t, color.NRGBA{A: 255}, color.NRGBAModel.Convert(entry.Image.At(1, 0)))
+	_, _, _, a := entry.Image.At(0, 1).RGBA()
+	assert.Equal(t, uint32(0), a)
+}
+
+func TestExtractIco(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.ico")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create ICO: %v", err)
+	}
+	assert.NoError(t, EncodeIco(f, []image.Image{testImage(16), testImage(32)}))
+	f.Close()
+
+	paths, err := ExtractIco(path, filepath.Join(dir, "out"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "out", "app-16x16-32bpp.png"),
+		filepath.Join(dir, "out", "app-32x32-32bpp.png"),
+	}, paths)
+}
@@ -0,0 +1,326 @@
+package image_convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// IconSetPreset writes a complete icon set for a platform, including its manifests.
+type IconSetPreset struct {
+	Name        string
+	Description string
+
+	// Generate writes the icon set for the app name into outputDir and returns the written paths.
+	Generate func(img image.Image, outputDir, name string, opts ResizeOptions) ([]string, error)
+}
+
+// IconSetPresets lists every platform preset.
+var IconSetPresets = []IconSetPreset{
+	{"android", "Android mipmap folders with adaptive icons", GenerateAndroidIcons},
+	{"ios", "iOS AppIcon.appiconset with Contents.json", GenerateIOSIcons},
+	{"linux", "Linux hicolor theme directories with a .desktop entry", GenerateLinuxIcons},
+}
+
+// GetIconSetPreset returns the platform preset with the given name.
+func GetIconSetPreset(name string) (IconSetPreset, error) {
+	for _, preset := range IconSetPresets {
+		if strings.EqualFold(preset.Name, name) {
+			return preset, nil
+		}
+	}
+	return IconSetPreset{}, fmt.Errorf("unknown icon set preset %q", name)
+}
+
+// ConvertImageToIconSet writes the icon set of the named preset into "./<basename>/<preset>/".
+// The resize options default to DefaultResizeOptions.
+func ConvertImageToIconSet(inputPath string, presetName string, opts ...ResizeOptions) error {
+	preset, err := GetIconSetPreset(presetName)
+	if err != nil {
+		return err
+	}
+	return convertTo(inputPath, nil, OutputFormat(preset.Name), opts)
+}
+
+// AndroidDensities maps the Android density buckets to their scale relative to mdpi.
+var AndroidDensities = []struct {
+	Name  string
+	Scale float64
+}{
+	{"mdpi", 1},
+	{"hdpi", 1.5},
+	{"xhdpi", 2},
+	{"xxhdpi", 3},
+	{"xxxhdpi", 4},
+}
+
+// Android launcher icon sizes in dp. Adaptive icon layers are 108dp with the
+// visible content kept inside the 66dp safe zone.
+const (
+	androidLauncherSize = 48
+	androidAdaptiveSize = 108
+	androidSafeZone     = 66
+	androidPlayStore    = 512
+)
+
+// androidAdaptiveIcon is the XML of an adaptive launcher icon.
+const androidAdaptiveIcon = `<?xml version="1.0" encoding="utf-8"?>
+<adaptive-icon xmlns:android="http://schemas.android.com/apk/res/android">
+    <background android:drawable="@color/ic_launcher_background"/>
+    <foreground android:drawable="@mipmap/ic_launcher_foreground"/>
+</adaptive-icon>
+`
+
+// GenerateAndroidIcons writes the legacy, round and adaptive launcher icons into
+// res/mipmap-*dpi folders, the adaptive icon XML and background color resource,
+// and the Play Store icon. The adaptive background uses opts.Background, white by default.
+func GenerateAndroidIcons(img image.Image, outputDir, name string, opts ResizeOptions) ([]string, error) {
+	var paths []string
+	res := filepath.Join(outputDir, "res")
+
+	background := opts.Background
+	if background == nil {
+		background = color.White
+	}
+
+	for _, density := range AndroidDensities {
+		dir := filepath.Join(res, "mipmap-"+density.Name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return paths, err
+		}
+
+		size := int(math.Round(androidLauncherSize * density.Scale))
+		launcher := Resize(img, image.Pt(size, size), opts)
+		files := map[string]image.Image{
+			"ic_launcher.png":       launcher,
+			"ic_launcher_round.png": circleMask(launcher),
+		}
+
+		// The foreground layer keeps the image inside the safe zone.
+		layer := int(math.Round(androidAdaptiveSize * density.Scale))
+		content := int(math.Round(androidSafeZone * density.Scale))
+		foregroundOpts := opts
+		foregroundOpts.Background = nil
+		foreground := image.NewRGBA(image.Rect(0, 0, layer, layer))
+		offset := (layer - content) / 2
+		draw.Draw(foreground, image.Rect(offset, offset, offset+content, offset+content),
+			Resize(img, image.Pt(content, content), foregroundOpts), image.Point{}, draw.Src)
+		files["ic_launcher_foreground.png"] = foreground
+
+		for _, file := range []string{"ic_launcher.png", "ic_launcher_round.png", "ic_launcher_foreground.png"} {
+			path := filepath.Join(dir, file)
+			if err := savePNG(path, files[file]); err != nil {
+				return paths, fmt.Errorf("error writing %s: %w", path, err)
+			}
+			paths = append(paths, path)
+		}
+	}
+
+	// Adaptive icon definitions
+	anydpi := filepath.Join(res, "mipmap-anydpi-v26")
+	values := filepath.Join(res, "values")
+	for _, dir := range []string{anydpi, values} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return paths, err
+		}
+	}
+	texts := []struct{ path, content string }{
+		{filepath.Join(anydpi, "ic_launcher.xml"), androidAdaptiveIcon},
+		{filepath.Join(anydpi, "ic_launcher_round.xml"), androidAdaptiveIcon},
+		{filepath.Join(values, "ic_launcher_background.xml"), fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <color name="ic_launcher_background">%s</color>
+</resources>
+`, hexColor(background))},
+	}
+	for _, text := range texts {
+		if err := os.WriteFile(text.path, []byte(text.content), 0644); err != nil {
+			return paths, fmt.Errorf("error writing %s: %w", text.path, err)
+		}
+		paths = append(paths, text.path)
+	}
+
+	// Play Store icon, which must be opaque.
+	playStore := filepath.Join(outputDir, "ic_launcher-playstore.png")
+	if err := savePNG(playStore, flatten(Resize(img, image.Pt(androidPlayStore, androidPlayStore), opts), background)); err != nil {
+		return paths, fmt.Errorf("error writing %s: %w", playStore, err)
+	}
+	paths = append(paths, playStore)
+
+	return paths, nil
+}
+
+// IOSIcon is an entry of an iOS app icon set.
+type IOSIcon struct {
+	Idiom string
+	Size  float64 // Size in points.
+	Scale int
+}
+
+// iosContentsImage is an image entry of the Contents.json of an app icon set.
+type iosContentsImage struct {
+	Size     string `json:"size"`
+	Idiom    string `json:"idiom"`
+	Filename string `json:"filename"`
+	Scale    string `json:"scale"`
+}
+
+// IOSIcons are the icons of an AppIcon.appiconset for iPhone, iPad and the App Store.
+var IOSIcons = []IOSIcon{
+	{Idiom: "iphone", Size: 20, Scale: 2},
+	{Idiom: "iphone", Size: 20, Scale: 3},
+	{Idiom: "iphone", Size: 29, Scale: 2},
+	{Idiom: "iphone", Size: 29, Scale: 3},
+	{Idiom: "iphone", Size: 40, Scale: 2},
+	{Idiom: "iphone", Size: 40, Scale: 3},
+	{Idiom: "iphone", Size: 60, Scale: 2},
+	{Idiom: "iphone", Size: 60, Scale: 3},
+	{Idiom: "ipad", Size: 20, Scale: 1},
+	{Idiom: "ipad", Size: 20, Scale: 2},
+	{Idiom: "ipad", Size: 29, Scale: 1},
+	{Idiom: "ipad", Size: 29, Scale: 2},
+	{Idiom: "ipad", Size: 40, Scale: 1},
+	{Idiom: "ipad", Size: 40, Scale: 2},
+	{Idiom: "ipad", Size: 76, Scale: 1},
+	{Idiom: "ipad", Size: 76, Scale: 2},
+	{Idiom: "ipad", Size: 83.5, Scale: 2},
+	{Idiom: "ios-marketing", Size: 1024, Scale: 1},
+}
+
+// GenerateIOSIcons writes an AppIcon.appiconset with every size in IOSIcons and its Contents.json.
+// iOS icons can't be transparent, so they are drawn on opts.Background, white by default.
+func GenerateIOSIcons(img image.Image, outputDir, name string, opts ResizeOptions) ([]string, error) {
+	dir := filepath.Join(outputDir, "AppIcon.appiconset")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	background := opts.Background
+	if background == nil {
+		background = color.White
+	}
+
+	var paths []string
+	written := make(map[string]bool)
+	images := make([]iosContentsImage, len(IOSIcons))
+	for i, icon := range IOSIcons {
+		points := fmt.Sprintf("%g", icon.Size)
+		images[i] = iosContentsImage{
+			Size:     points + "x" + points,
+			Idiom:    icon.Idiom,
+			Filename: fmt.Sprintf("Icon-%s@%dx.png", points, icon.Scale),
+			Scale:    fmt.Sprintf("%dx", icon.Scale),
+		}
+
+		// iPhone and iPad share some files.
+		filename := images[i].Filename
+		if written[filename] {
+			continue
+		}
+		written[filename] = true
+
+		pixels := int(math.Round(icon.Size * float64(icon.Scale)))
+		path := filepath.Join(dir, filename)
+		if err := savePNG(path, flatten(Resize(img, image.Pt(pixels, pixels), opts), background)); err != nil {
+			return paths, fmt.Errorf("error writing %s: %w", filename, err)
+		}
+		paths = append(paths, path)
+	}
+
+	contents := struct {
+		Images []iosContentsImage `json:"images"`
+		Info   struct {
+			Version int    `json:"version"`
+			Author  string `json:"author"`
+		} `json:"info"`
+	}{Images: images}
+	contents.Info.Version = 1
+	contents.Info.Author = "xcode"
+
+	data, err := json.MarshalIndent(contents, "", "  ")
+	if err != nil {
+		return paths, err
+	}
+	path := filepath.Join(dir, "Contents.json")
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return paths, fmt.Errorf("error writing Contents.json: %w", err)
+	}
+
+	return append(paths, path), nil
+}
+
+// LinuxIconSizes are the hicolor theme sizes written by GenerateLinuxIcons.
+var LinuxIconSizes = []int{16, 22, 24, 32, 48, 64, 128, 256, 512}
+
+// GenerateLinuxIcons writes "hicolor/<size>x<size>/apps/<name>.png" for every size in
+// LinuxIconSizes and a "<name>.desktop" entry referencing the icon by name.
+func GenerateLinuxIcons(img image.Image, outputDir, name string, opts ResizeOptions) ([]string, error) {
+	var paths []string
+	iconName := strings.ToLower(strings.Join(strings.Fields(name), "-"))
+
+	for _, size := range LinuxIconSizes {
+		dir := filepath.Join(outputDir, "hicolor", fmt.Sprintf("%dx%d", size, size), "apps")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return paths, err
+		}
+		path := filepath.Join(dir, iconName+".png")
+		if err := savePNG(path, Resize(img, image.Pt(size, size), opts)); err != nil {
+			return paths, fmt.Errorf("error writing %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+
+	desktop := fmt.Sprintf("[Desktop Entry]\nType=Application\nName=%s\nExec=%s\nIcon=%s\nTerminal=false\n", name, iconName, iconName)
+	path := filepath.Join(outputDir, iconName+".desktop")
+	if err := os.WriteFile(path, []byte(desktop), 0644); err != nil {
+		return paths, fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	return append(paths, path), nil
+}
+
+// flatten draws img on an opaque background.
+func flatten(img image.Image, background color.Color) *image.RGBA {
+	out := image.NewRGBA(img.Bounds())
+	draw.Draw(out, out.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	draw.Draw(out, out.Bounds(), img, img.Bounds().Min, draw.Over)
+	return out
+}
+
+// circleMask returns img with everything outside the inscribed circle made transparent.
+// Edge pixels are partially covered, which anti-aliases the circle.
+func circleMask(img *image.RGBA) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+	cx, cy := float64(b.Dx())/2, float64(b.Dy())/2
+	r := math.Min(cx, cy)
+
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			d := math.Hypot(float64(x)+0.5-cx, float64(y)+0.5-cy)
+			coverage := math.Max(0, math.Min(1, r-d+0.5))
+			if coverage == 0 {
+				continue
+			}
+			i := img.PixOffset(b.Min.X+x, b.Min.Y+y)
+			for c := 0; c < 4; c++ {
+				out.Pix[i+c] = uint8(math.Round(float64(img.Pix[i+c]) * coverage))
+			}
+		}
+	}
+
+	return out
+}
+
+// hexColor formats c as "#RRGGBB", ignoring its alpha.
+func hexColor(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02X%02X%02X", n.R, n.G, n.B)
+}
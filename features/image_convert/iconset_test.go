@@ -0,0 +1,80 @@
+package image_convert
+
+import (
+	"encoding/json"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAndroidIcons(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := GenerateAndroidIcons(testImage(64), dir, "Sterben", DefaultResizeOptions); err != nil {
+		t.Fatalf("Failed to generate Android icons: %v", err)
+	}
+
+	file, err := os.Open(filepath.Join(dir, "res", "mipmap-xxxhdpi", "ic_launcher_foreground.png"))
+	if err != nil {
+		t.Fatalf("Failed to open foreground: %v", err)
+	}
+	defer file.Close()
+	foreground, err := png.Decode(file)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Pt(432, 432), foreground.Bounds().Size())
+
+	// The round icon is transparent in the corners.
+	round, err := os.Open(filepath.Join(dir, "res", "mipmap-mdpi", "ic_launcher_round.png"))
+	assert.NoError(t, err)
+	defer round.Close()
+	img, err := png.Decode(round)
+	assert.NoError(t, err)
+	_, _, _, a := img.At(47, 0).RGBA()
+	assert.Equal(t, uint32(0), a)
+
+	background, err := os.ReadFile(filepath.Join(dir, "res", "values", "ic_launcher_background.xml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(background), "#FFFFFF")
+	assert.FileExists(t, filepath.Join(dir, "res", "mipmap-anydpi-v26", "ic_launcher.xml"))
+}
+
+func TestGenerateIOSIcons(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := GenerateIOSIcons(testImage(64), dir, "Sterben", DefaultResizeOptions); err != nil {
+		t.Fatalf("Failed to generate iOS icons: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "AppIcon.appiconset", "Contents.json"))
+	if err != nil {
+		t.Fatalf("Failed to read Contents.json: %v", err)
+	}
+	var contents struct {
+		Images []iosContentsImage `json:"images"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &contents))
+	assert.Len(t, contents.Images, len(IOSIcons))
+
+	for _, img := range contents.Images {
+		assert.FileExists(t, filepath.Join(dir, "AppIcon.appiconset", img.Filename))
+	}
+	assert.Equal(t, "83.5x83.5", contents.Images[16].Size)
+	assert.Equal(t, "Icon-83.5@2x.png", contents.Images[16].Filename)
+}
+
+func TestGenerateLinuxIcons(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := GenerateLinuxIcons(testImage(64), dir, "My App", DefaultResizeOptions); err != nil {
+		t.Fatalf("Failed to generate Linux icons: %v", err)
+	}
+
+	assert.FileExists(t, filepath.Join(dir, "hicolor", "48x48", "apps", "my-app.png"))
+	desktop, err := os.ReadFile(filepath.Join(dir, "my-app.desktop"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(desktop), "Icon=my-app\n")
+
+	_, err = GetIconSetPreset("windows")
+	assert.Error(t, err)
+}
@@ -3,25 +3,14 @@ package image_convert
 import (
 	"fmt"
 	"image"
-	"image/jpeg"
-	"image/png"
+	"image/color"
 	"os"
 	"path/filepath"
 	"strings"
 
-	ico "github.com/Kodeworks/golang-image-ico"
 	"golang.org/x/image/draw"
 )
 
-// SupportedImageType represents the type of image supported
-type SupportedImageType int
-
-const (
-	PNG SupportedImageType = iota
-	JPEG
-	WEBP
-)
-
 var Sizes []image.Point = []image.Point{
 	{16, 16},
 	{24, 24},
@@ -32,79 +21,48 @@ var Sizes []image.Point = []image.Point{
 	{256, 256},
 }
 
-func ConvertImageToMultipleIcons(inputPath string, sizes []image.Point) error {
-	// Expand the inputPath to handle the tilde (~) if present
-	expandedInputPath, err := expandPath(inputPath)
-	if err != nil {
-		return fmt.Errorf("error expanding input path: %w", err)
-	}
-
-	// Open the input image file
-	file, err := os.Open(expandedInputPath)
-	if err != nil {
-		return fmt.Errorf("error opening image: %w", err)
-	}
-	defer file.Close()
-
-	var fileName string = "./" + filepath.Base(file.Name())
-	// remove extension
-	fileName = strings.TrimSuffix(fileName, filepath.Ext(fileName))
-
-	// Determine the image type
-	imgType, err := getImageType(file)
-	if err != nil {
-		return fmt.Errorf("error determining image type: %w", err)
-	}
-
-	// Seek to the beginning of the file after reading the header
-	file.Seek(0, 0)
-
-	// Decode the image
-	var img image.Image
-	switch imgType {
-	case PNG:
-		img, err = png.Decode(file)
-	case JPEG:
-		img, err = jpeg.Decode(file)
-	// Add WEBP decoding if needed
-	default:
-		return fmt.Errorf("unsupported image format")
-	}
-	if err != nil {
-		return fmt.Errorf("error decoding image: %w", err)
-	}
-
-	// Create the output directory if it doesn't exist
-	if err := makeDirectoryIfNotExists(fileName); err != nil {
-		return fmt.Errorf("error creating directory: %w", err)
-	}
+// ConvertImageToMultiSizeIcon writes a single ICO file containing every size
+// into "./<basename>/<basename>.ico". This is the format Windows expects.
+// The resize options default to DefaultResizeOptions.
+func ConvertImageToMultiSizeIcon(inputPath string, sizes []image.Point, opts ...ResizeOptions) error {
+	return convertTo(inputPath, sizes, FormatICO, opts)
+}
 
-	// Create ICO files for each size
-	for _, size := range sizes {
-		resizedImg := resizeImage(img, size)
-		rgbaImg := convertToRGBA(resizedImg)
+// ConvertImageToIcns writes a macOS ICNS file with every type in IcnsTypes
+// into "./<basename>/<basename>.icns". The resize options default to DefaultResizeOptions.
+func ConvertImageToIcns(inputPath string, opts ...ResizeOptions) error {
+	return convertTo(inputPath, nil, FormatICNS, opts)
+}
 
-		// Prepare the output ICO file path
-		outputPath := filepath.Join(fileName, fmt.Sprintf("%dx%d.ico", size.X, size.Y))
-		icoFile, err := os.Create(outputPath)
-		if err != nil {
-			return fmt.Errorf("error creating ICO file for size %dx%d: %w", size.X, size.Y, err)
-		}
+// ConvertImageToMultipleIcons writes a separate ICO file for each size into "./<basename>/<width>x<height>.ico".
+// The resize options default to DefaultResizeOptions.
+func ConvertImageToMultipleIcons(inputPath string, sizes []image.Point, opts ...ResizeOptions) error {
+	return convertTo(inputPath, sizes, FormatICOPerSize, opts)
+}
 
-		// Encode the resized image as an ICO file
-		if err := ico.Encode(icoFile, rgbaImg); err != nil {
-			icoFile.Close()
-			return fmt.Errorf("error encoding ICO for size %dx%d: %w", size.X, size.Y, err)
-		}
+func ConvertImageToIcon(inputPath string, size image.Point, opts ...ResizeOptions) error {
+	return ConvertImageToMultipleIcons(inputPath, []image.Point{size}, opts...)
+}
 
-		icoFile.Close()
+// convertTo runs Convert with the default options for a single output format.
+func convertTo(inputPath string, sizes []image.Point, format OutputFormat, opts []ResizeOptions) error {
+	options := DefaultOptions()
+	if sizes != nil {
+		options.Sizes = sizes
 	}
+	options.Resize = resizeOptions(opts)
+	options.Formats = []OutputFormat{format}
 
-	return nil
+	_, err := Convert(inputPath, options)
+	return err
 }
 
-func ConvertImageToIcon(inputPath string, size image.Point) error {
-	return ConvertImageToMultipleIcons(inputPath, []image.Point{size})
+// resizeOptions returns the first of opts, or DefaultResizeOptions if none are given.
+func resizeOptions(opts []ResizeOptions) ResizeOptions {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return DefaultResizeOptions
 }
 
 // convertToRGBA converts the image to RGBA format
@@ -114,41 +72,10 @@ func convertToRGBA(img image.Image) *image.RGBA {
 	return rgba
 }
 
-// resizeImage resizes the image to the specified size
-func resizeImage(img image.Image, size image.Point) image.Image {
-	resizedImg := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
-	draw.NearestNeighbor.Scale(resizedImg, resizedImg.Bounds(), img, img.Bounds(), draw.Src, nil)
-	return resizedImg
-}
-
-// getImageType determines the image type based on the file signature
-func getImageType(file *os.File) (SupportedImageType, error) {
-	header := make([]byte, 512)
-	_, err := file.Read(header)
-	if err != nil {
-		return -1, err
-	}
-
-	if header[1] == 'P' && header[2] == 'N' && header[3] == 'G' {
-		return PNG, nil
-	} else if header[6] == 'J' && header[7] == 'F' && header[8] == 'I' && header[9] == 'F' {
-		return JPEG, nil
-	} else if header[8] == 'W' && header[9] == 'E' && header[10] == 'B' && header[11] == 'P' {
-		return WEBP, nil
-	} else {
-		return -1, fmt.Errorf("unsupported image type")
-	}
-}
-
-// makeDirectoryIfNotExists creates a directory if it doesn't exist
-func makeDirectoryIfNotExists(path string) error {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		err := os.Mkdir(path, 0755)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+// nrgbaAt returns the non-premultiplied color of the pixel at x, y relative to the image bounds.
+func nrgbaAt(img *image.RGBA, x, y int) color.NRGBA {
+	min := img.Bounds().Min
+	return color.NRGBAModel.Convert(img.RGBAAt(min.X+x, min.Y+y)).(color.NRGBA)
 }
 
 // Expand the tilde (~) in the input path to the user's home directory
@@ -0,0 +1,444 @@
+package image_convert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrTargetNotMet is returned with the smallest JPEG tried when none fits OptimizeOptions.TargetSize.
+var ErrTargetNotMet = errors.New("target size not met")
+
+// OptimizeOptions configures Optimize.
+type OptimizeOptions struct {
+	// TargetSize is the largest JPEG output in bytes, kept metadata included, 0 for no budget.
+	TargetSize int64
+	// MinSSIM is the lowest JPEG similarity to the decoded input, from 0 to 1, 0 to ignore.
+	// With a TargetSize as well, the smaller of the two qualities is used.
+	MinSSIM float64
+	// MinQuality and MaxQuality bound the JPEG qualities tried.
+	MinQuality, MaxQuality int
+	// StripMetadata drops EXIF, XMP, ICC and text metadata.
+	StripMetadata bool
+}
+
+// DefaultOptimizeOptions keeps JPEGs visually identical to the input.
+var DefaultOptimizeOptions = OptimizeOptions{
+	MinSSIM:    0.98,
+	MinQuality: 10,
+	MaxQuality: 95,
+}
+
+// OptimizeResult reports what Optimize did to a file.
+type OptimizeResult struct {
+	Input     string
+	Output    string
+	Format    string
+	Before    int64   // Size of the input in bytes.
+	After     int64   // Size of the output in bytes.
+	Quality   int     // JPEG quality used, 0 for PNGs.
+	SSIM      float64 // Similarity of the JPEG to the decoded input, 0 for PNGs.
+	Colors    int     // Colors of the PNG palette, 0 if the PNG isn't paletted.
+	Unchanged bool    // The input was already the smallest and was written as is.
+	Err       error
+}
+
+// Saved returns the fraction of the input size saved, from 0 to 1.
+func (r OptimizeResult) Saved() float64 {
+	if r.Before == 0 {
+		return 0
+	}
+	return 1 - float64(r.After)/float64(r.Before)
+}
+
+// String describes the result on one line, like "photo.jpg: 120000 -> 45000 bytes (-62%, quality 71)".
+func (r OptimizeResult) String() string {
+	name := filepath.Base(r.Input)
+	if r.Err != nil && !errors.Is(r.Err, ErrTargetNotMet) {
+		return fmt.Sprintf("%s: %v", name, r.Err)
+	}
+
+	var details []string
+	details = append(details, fmt.Sprintf("-%.0f%%", r.Saved()*100))
+	switch {
+	case r.Unchanged:
+		details = append(details, "already optimal")
+	case r.Quality > 0:
+		details = append(details, fmt.Sprintf("quality %d, ssim %.3f", r.Quality, r.SSIM))
+	case r.Colors > 0:
+		details = append(details, fmt.Sprintf("%d colors", r.Colors))
+	}
+	if r.Err != nil {
+		details = append(details, r.Err.Error())
+	}
+	return fmt.Sprintf("%s: %d -> %d bytes (%s)", name, r.Before, r.After, strings.Join(details, ", "))
+}
+
+// Optimize re-encodes a PNG or JPEG to make it smaller. PNGs are reduced losslessly, to a palette
+// or grayscale when every color fits, and compressed as much as possible. JPEGs get the quality
+// picked by a binary search against the size budget and similarity of opts. The input is
+// returned as is if it is already smaller.
+func Optimize(data []byte, opts OptimizeOptions) ([]byte, OptimizeResult, error) {
+	result := OptimizeResult{Before: int64(len(data))}
+
+	img, format, err := DecodeImage(bytes.NewReader(data))
+	if err != nil {
+		return nil, result, err
+	}
+	result.Format = format
+
+	var out []byte
+	switch format {
+	case "png":
+		out, result.Colors, err = optimizePNG(img)
+		if err == nil && !opts.StripMetadata {
+			out = insertPNGChunks(out, pngMetadata(data))
+		}
+	case "jpeg":
+		var metadata [][]byte
+		if !opts.StripMetadata {
+			metadata = jpegMetadata(data)
+		}
+		// The metadata added back counts against the size budget.
+		jpegOpts := opts
+		if opts.TargetSize > 0 {
+			jpegOpts.TargetSize = max(1, opts.TargetSize-segmentsSize(metadata))
+		}
+		out, result.Quality, result.SSIM, err = optimizeJPEG(img, jpegOpts)
+		if err == nil || errors.Is(err, ErrTargetNotMet) {
+			out = insertJPEGSegments(out, metadata)
+		}
+		if errors.Is(err, ErrTargetNotMet) && len(metadata) > 0 {
+			err = fmt.Errorf("%w: %d bytes with metadata at quality %d", ErrTargetNotMet, len(out), result.Quality)
+		}
+	default:
+		return nil, result, fmt.Errorf("can't optimize %s images, only png and jpeg", format)
+	}
+	if out == nil {
+		return nil, result, err
+	}
+
+	// Stripping metadata is worth an output larger than the input, missing the target isn't.
+	if int64(len(out)) >= result.Before && (!opts.StripMetadata || err != nil) {
+		out = data
+		result.Unchanged = true
+		result.Quality, result.SSIM, result.Colors = 0, 0, 0
+	}
+	result.After = int64(len(out))
+	return out, result, err
+}
+
+// OptimizeFile optimizes the image at inputPath and writes it to outputPath, which may be the
+// input itself. An empty outputPath writes "<name>-optimized<ext>" next to the input.
+func OptimizeFile(inputPath, outputPath string, opts OptimizeOptions) OptimizeResult {
+	result := OptimizeResult{Input: inputPath}
+
+	expandedInputPath, err := expandPath(inputPath)
+	if err != nil {
+		result.Err = fmt.Errorf("error expanding input path: %w", err)
+		return result
+	}
+	if outputPath == "" {
+		ext := filepath.Ext(expandedInputPath)
+		outputPath = strings.TrimSuffix(expandedInputPath, ext) + "-optimized" + ext
+	}
+
+	data, err := os.ReadFile(expandedInputPath)
+	if err != nil {
+		result.Err = fmt.Errorf("error reading image: %w", err)
+		return result
+	}
+
+	out, r, err := Optimize(data, opts)
+	r.Input = inputPath
+	if out == nil {
+		r.Err = err
+		return r
+	}
+	if werr := os.WriteFile(outputPath, out, 0644); werr != nil {
+		r.Err = fmt.Errorf("error writing image: %w", werr)
+		return r
+	}
+	r.Output = outputPath
+	r.Err = err
+	return r
+}
+
+// OptimizeFiles optimizes every image matched by inputs, see ExpandInputs. Outputs are written
+// to outputDir with their input names, or next to their input if it is empty.
+func OptimizeFiles(inputs []string, outputDir string, opts OptimizeOptions) ([]OptimizeResult, error) {
+	paths, err := ExpandInputs(inputs)
+	if err != nil {
+		return nil, err
+	}
+	if outputDir != "" {
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return nil, fmt.Errorf("error creating directory: %w", err)
+		}
+	}
+
+	results := make([]OptimizeResult, len(paths))
+	for i, path := range paths {
+		var outputPath string
+		if outputDir != "" {
+			outputPath = filepath.Join(outputDir, filepath.Base(path))
+		}
+		results[i] = OptimizeFile(path, outputPath, opts)
+	}
+	return results, nil
+}
+
+// segmentsSize returns the combined size of segments in bytes.
+func segmentsSize(segments [][]byte) int64 {
+	var size int64
+	for _, segment := range segments {
+		size += int64(len(segment))
+	}
+	return size
+}
+
+// optimizePNG encodes img and its lossless reductions with the best compression and returns the
+// smallest, with the number of palette colors, 0 if it isn't paletted.
+func optimizePNG(img image.Image) ([]byte, int, error) {
+	var best []byte
+	var colors int
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	for _, candidate := range losslessReductions(img) {
+		var buf bytes.Buffer
+		if err := encoder.Encode(&buf, candidate); err != nil {
+			return nil, 0, err
+		}
+		if best == nil || buf.Len() < len(best) {
+			best = buf.Bytes()
+			colors = 0
+			if p, ok := candidate.(*image.Paletted); ok {
+				colors = len(p.Palette)
+			}
+		}
+	}
+	return best, colors, nil
+}
+
+// losslessReductions returns img and the versions of it with the same pixels in fewer bytes:
+// a paletted image if it has at most 256 colors, and a gray one if it is opaque and gray.
+// Both are 8-bit, so images with colors that need 16 bits are never reduced.
+func losslessReductions(img image.Image) []image.Image {
+	b := img.Bounds()
+	index := map[color.NRGBA]uint8{}
+	var p color.Palette
+	paletted, gray := true, true
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c, ok := nrgba8(img.At(x, y))
+			if !ok {
+				return []image.Image{img}
+			}
+			if c.A != 255 || c.R != c.G || c.G != c.B {
+				gray = false
+			}
+			if _, ok := index[c]; ok || !paletted {
+				continue
+			}
+			if len(p) == 256 {
+				paletted = false
+				continue
+			}
+			index[c] = uint8(len(p))
+			p = append(p, c)
+		}
+	}
+
+	images := []image.Image{img}
+	if paletted {
+		pi := image.NewPaletted(b, p)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				c, _ := nrgba8(img.At(x, y))
+				pi.SetColorIndex(x, y, index[c])
+			}
+		}
+		images = append(images, pi)
+	}
+	if gray {
+		g := image.NewGray(b)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				g.SetGray(x, y, color.GrayModel.Convert(img.At(x, y)).(color.Gray))
+			}
+		}
+		images = append(images, g)
+	}
+	return images
+}
+
+// nrgba8 returns c as 8-bit non-premultiplied color, and whether that keeps it exactly.
+func nrgba8(c color.Color) (color.NRGBA, bool) {
+	c64 := color.NRGBA64Model.Convert(c).(color.NRGBA64)
+	c8 := color.NRGBA{uint8(c64.R >> 8), uint8(c64.G >> 8), uint8(c64.B >> 8), uint8(c64.A >> 8)}
+	exact := uint16(c8.R)*0x101 == c64.R && uint16(c8.G)*0x101 == c64.G &&
+		uint16(c8.B)*0x101 == c64.B && uint16(c8.A)*0x101 == c64.A
+	return c8, exact
+}
+
+// optimizeJPEG returns img encoded at the lowest quality meeting opts.MinSSIM, lowered further to
+// fit opts.TargetSize. It returns ErrTargetNotMet with the smallest encoding if nothing fits.
+func optimizeJPEG(img image.Image, opts OptimizeOptions) ([]byte, int, float64, error) {
+	lo, hi := opts.MinQuality, opts.MaxQuality
+	if lo <= 0 {
+		lo = DefaultOptimizeOptions.MinQuality
+	}
+	if hi <= 0 {
+		hi = DefaultOptimizeOptions.MaxQuality
+	}
+	if lo > hi || hi > 100 {
+		return nil, 0, 0, fmt.Errorf("invalid JPEG quality range %d to %d", lo, hi)
+	}
+
+	opaque := flatten(img, color.White)
+	type encoding struct {
+		data []byte
+		ssim float64
+	}
+	encodings := map[int]encoding{}
+	encode := func(quality int) (encoding, error) {
+		if e, ok := encodings[quality]; ok {
+			return e, nil
+		}
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, opaque, &jpeg.Options{Quality: quality}); err != nil {
+			return encoding{}, err
+		}
+		e := encoding{data: buf.Bytes()}
+		if opts.MinSSIM > 0 {
+			decoded, err := jpeg.Decode(bytes.NewReader(e.data))
+			if err != nil {
+				return encoding{}, err
+			}
+			e.ssim = SSIM(opaque, decoded)
+		}
+		encodings[quality] = e
+		return e, nil
+	}
+
+	// search returns the lowest quality in lo..hi for which ok holds, or hi+1 if there is none.
+	search := func(ok func(encoding) bool) (int, error) {
+		l, h := lo, hi+1
+		for l < h {
+			q := (l + h) / 2
+			e, err := encode(q)
+			if err != nil {
+				return 0, err
+			}
+			if ok(e) {
+				h = q
+			} else {
+				l = q + 1
+			}
+		}
+		return l, nil
+	}
+
+	quality := hi
+	if opts.MinSSIM > 0 {
+		q, err := search(func(e encoding) bool { return e.ssim >= opts.MinSSIM })
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		quality = min(q, hi)
+	}
+
+	var err error
+	if opts.TargetSize > 0 {
+		// The highest quality within the budget is one below the lowest quality above it.
+		q, serr := search(func(e encoding) bool { return int64(len(e.data)) > opts.TargetSize })
+		if serr != nil {
+			return nil, 0, 0, serr
+		}
+		if q == lo {
+			err = fmt.Errorf("%w: %d bytes at quality %d", ErrTargetNotMet, len(encodings[lo].data), lo)
+			quality = lo
+		} else {
+			quality = min(quality, q-1)
+		}
+	}
+
+	e, eerr := encode(quality)
+	if eerr != nil {
+		return nil, 0, 0, eerr
+	}
+	if e.ssim == 0 {
+		decoded, derr := jpeg.Decode(bytes.NewReader(e.data))
+		if derr != nil {
+			return nil, 0, 0, derr
+		}
+		e.ssim = SSIM(opaque, decoded)
+	}
+	return e.data, quality, e.ssim, err
+}
+
+// ssimWindow is the size of the windows SSIM compares, moved by half of it.
+const ssimWindow = 8
+
+// SSIM returns the mean structural similarity of the luma of two images of the same size, from
+// 0 for unrelated images to 1 for identical ones, over 8x8 windows.
+func SSIM(a, b image.Image) float64 {
+	ab, bb := a.Bounds(), b.Bounds()
+	w, h := min(ab.Dx(), bb.Dx()), min(ab.Dy(), bb.Dy())
+	if w == 0 || h == 0 {
+		return 0
+	}
+
+	luma := func(img image.Image, min image.Point) []float64 {
+		l := make([]float64, w*h)
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				r, g, b, _ := img.At(min.X+x, min.Y+y).RGBA()
+				l[y*w+x] = (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 257
+			}
+		}
+		return l
+	}
+	la, lb := luma(a, ab.Min), luma(b, bb.Min)
+
+	const c1, c2 = (0.01 * 255) * (0.01 * 255), (0.03 * 255) * (0.03 * 255)
+	size := min(ssimWindow, w, h)
+	step := max(1, size/2)
+
+	var sum float64
+	var windows int
+	for y := 0; y+size <= h; y += step {
+		for x := 0; x+size <= w; x += step {
+			var ma, mb float64
+			for j := y; j < y+size; j++ {
+				for i := x; i < x+size; i++ {
+					ma += la[j*w+i]
+					mb += lb[j*w+i]
+				}
+			}
+			n := float64(size * size)
+			ma, mb = ma/n, mb/n
+
+			var va, vb, cov float64
+			for j := y; j < y+size; j++ {
+				for i := x; i < x+size; i++ {
+					da, db := la[j*w+i]-ma, lb[j*w+i]-mb
+					va += da * da
+					vb += db * db
+					cov += da * db
+				}
+			}
+			va, vb, cov = va/n, vb/n, cov/n
+
+			sum += ((2*ma*mb + c1) * (2*cov + c2)) / ((ma*ma + mb*mb + c1) * (va + vb + c2))
+			windows++
+		}
+	}
+	return sum / float64(windows)
+}
@@ -0,0 +1,190 @@
+package image_convert
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// photoImage returns a gradient with noise, which compresses like a photo.
+func photoImage(size int) *image.RGBA {
+	rng := rand.New(rand.NewSource(1))
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			n := uint8(rng.Intn(32))
+			img.Set(x, y, color.RGBA{uint8(x*255/size) ^ n, uint8(y*255/size) + n, 128 + n, 255})
+		}
+	}
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image, quality int) []byte {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		t.Fatalf("Failed to encode JPEG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestOptimizePNG(t *testing.T) {
+	// Few colors stored as 64-bit RGBA shrink to a palette without losing anything.
+	img := image.NewRGBA64(image.Rect(0, 0, 64, 64))
+	src := testImage(64)
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			img.Set(x, y, src.At(x, y))
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, (&png.Encoder{CompressionLevel: png.NoCompression}).Encode(&buf, img))
+	input := insertPNGChunks(buf.Bytes(), [][]byte{pngChunk("tEXt", []byte("Title\x00icon"))})
+
+	out, result, err := Optimize(input, OptimizeOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "png", result.Format)
+	assert.Equal(t, 2, result.Colors)
+	assert.Less(t, result.After, result.Before)
+	assert.Equal(t, int64(len(out)), result.After)
+	assert.Len(t, pngMetadata(out), 1)
+
+	decoded, err := png.Decode(bytes.NewReader(out))
+	assert.NoError(t, err)
+	for _, p := range []image.Point{{0, 0}, {63, 63}} {
+		assert.Equal(t, color.NRGBAModel.Convert(src.At(p.X, p.Y)), color.NRGBAModel.Convert(decoded.At(p.X, p.Y)))
+	}
+
+	// An input that is already optimal is kept as is.
+	again, result, err := Optimize(out, OptimizeOptions{})
+	assert.NoError(t, err)
+	assert.True(t, result.Unchanged)
+	assert.Equal(t, out, again)
+}
+
+func TestOptimizePNG16Bit(t *testing.T) {
+	gray := image.NewGray16(image.Rect(0, 0, 64, 64))
+	rgba := image.NewNRGBA64(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			v := uint16(3035 + (x/32)*1000)
+			gray.SetGray16(x, y, color.Gray16{v})
+			rgba.SetNRGBA64(x, y, color.NRGBA64{v, v / 2, 0xffff - v, 0xffff})
+		}
+	}
+
+	for _, img := range []image.Image{gray, rgba} {
+		var buf bytes.Buffer
+		assert.NoError(t, (&png.Encoder{CompressionLevel: png.NoCompression}).Encode(&buf, img))
+
+		out, result, err := Optimize(buf.Bytes(), OptimizeOptions{})
+		assert.NoError(t, err)
+		assert.Zero(t, result.Colors, "16-bit colors don't fit a palette")
+
+		decoded, err := png.Decode(bytes.NewReader(out))
+		assert.NoError(t, err)
+		assert.Equal(t, color.NRGBA64Model.Convert(img.At(0, 0)), color.NRGBA64Model.Convert(decoded.At(0, 0)))
+		assert.Equal(t, color.NRGBA64Model.Convert(img.At(63, 0)), color.NRGBA64Model.Convert(decoded.At(63, 0)))
+	}
+}
+
+func TestOptimizeJPEGTargetSize(t *testing.T) {
+	input := encodeJPEG(t, photoImage(128), 100)
+	target := int64(len(input) / 3)
+
+	out, result, err := Optimize(input, OptimizeOptions{TargetSize: target})
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, result.After, target)
+	assert.Equal(t, int64(len(out)), result.After)
+	assert.Less(t, result.Quality, DefaultOptimizeOptions.MaxQuality)
+	assert.Greater(t, result.SSIM, 0.0)
+
+	// One quality higher would not fit.
+	assert.Greater(t, int64(len(encodeJPEG(t, flatten(photoImage(128), color.White), result.Quality+1))), target)
+}
+
+func TestOptimizeJPEGTargetSizeMetadata(t *testing.T) {
+	plain := encodeJPEG(t, photoImage(128), 100)
+
+	// A large XMP block in an APP1 segment, kept by default.
+	payload := append([]byte("http://ns.adobe.com/xap/1.0/\x00"), bytes.Repeat([]byte(" "), 20000)...)
+	segment := append([]byte{0xff, 0xe1, byte((len(payload) + 2) >> 8), byte(len(payload) + 2)}, payload...)
+	input := insertJPEGSegments(plain, [][]byte{segment})
+	target := int64(len(segment) + len(plain)/3)
+
+	out, result, err := Optimize(input, OptimizeOptions{TargetSize: target})
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, result.After, target, "the metadata counts against the budget")
+	assert.True(t, bytes.Contains(out, payload))
+
+	_, _, err = Optimize(input, OptimizeOptions{TargetSize: int64(len(segment))})
+	assert.ErrorIs(t, err, ErrTargetNotMet)
+}
+
+func TestOptimizeJPEGSSIM(t *testing.T) {
+	input := encodeJPEG(t, photoImage(128), 100)
+
+	_, result, err := Optimize(input, OptimizeOptions{MinSSIM: 0.9})
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, result.SSIM, 0.9)
+	assert.Less(t, result.After, result.Before)
+
+	_, strict, err := Optimize(input, OptimizeOptions{MinSSIM: 0.99})
+	assert.NoError(t, err)
+	assert.Greater(t, strict.Quality, result.Quality)
+}
+
+func TestOptimizeJPEGTargetNotMet(t *testing.T) {
+	input := encodeJPEG(t, photoImage(128), 100)
+	out, result, err := Optimize(input, OptimizeOptions{TargetSize: 100, MinQuality: 50, MaxQuality: 90})
+	assert.ErrorIs(t, err, ErrTargetNotMet)
+	assert.NotNil(t, out, "the smallest encoding is still returned")
+	assert.Equal(t, 50, result.Quality)
+
+	// An input smaller than every encoding is kept, and the miss still reported.
+	small := encodeJPEG(t, photoImage(128), 10)
+	out, result, err = Optimize(small, OptimizeOptions{TargetSize: 100, MinQuality: 50, MaxQuality: 90})
+	assert.ErrorIs(t, err, ErrTargetNotMet)
+	assert.Equal(t, small, out)
+	assert.True(t, result.Unchanged)
+}
+
+func TestOptimizeUnsupported(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, EncodeImage(&buf, testImage(8), FormatOptions{Format: ImageGIF}))
+	_, _, err := Optimize(buf.Bytes(), DefaultOptimizeOptions)
+	assert.Error(t, err)
+}
+
+func TestSSIM(t *testing.T) {
+	img := photoImage(32)
+	assert.InDelta(t, 1.0, SSIM(img, img), 1e-9)
+	assert.Less(t, SSIM(img, image.NewRGBA(img.Bounds())), 0.1)
+}
+
+func TestOptimizeFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTestPNG(t, dir, "a.png", 32)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.jpg"), encodeJPEG(t, photoImage(64), 100), 0644))
+
+	out := filepath.Join(dir, "out")
+	results, err := OptimizeFiles([]string{dir}, out, DefaultOptimizeOptions)
+	assert.NoError(t, err)
+	if !assert.Len(t, results, 2) {
+		return
+	}
+	for _, r := range results {
+		assert.NoError(t, r.Err)
+		assert.Equal(t, filepath.Join(out, filepath.Base(r.Input)), r.Output)
+		assert.FileExists(t, r.Output)
+		assert.LessOrEqual(t, r.After, r.Before)
+		assert.Contains(t, r.String(), filepath.Base(r.Input)+": ")
+	}
+}
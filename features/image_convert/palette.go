@@ -0,0 +1,207 @@
+package image_convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PaletteMethod is the algorithm ExtractPalette groups colors with.
+type PaletteMethod int
+
+const (
+	MedianCut PaletteMethod = iota // Splits the widest color box where its two groups differ most.
+	KMeans                         // Refines median cut colors with k-means clustering.
+)
+
+// PaletteMethods lists every palette method.
+var PaletteMethods = []PaletteMethod{MedianCut, KMeans}
+
+// String returns the name of the method.
+func (m PaletteMethod) String() string {
+	if m == KMeans {
+		return "kmeans"
+	}
+	return "mediancut"
+}
+
+// Next returns the method after m in PaletteMethods, wrapping around.
+func (m PaletteMethod) Next() PaletteMethod {
+	return PaletteMethods[(int(m)+1)%len(PaletteMethods)]
+}
+
+// ParsePaletteMethod returns the palette method with the given name.
+func ParsePaletteMethod(name string) (PaletteMethod, error) {
+	for _, m := range PaletteMethods {
+		if strings.EqualFold(name, m.String()) {
+			return m, nil
+		}
+	}
+	return MedianCut, fmt.Errorf("unknown palette method %q", name)
+}
+
+// kMeansIterations is the largest number of k-means refinements.
+const kMeansIterations = 16
+
+// Swatch is a color of an extracted palette.
+type Swatch struct {
+	Color  color.NRGBA
+	Weight float64 // Fraction of the opaque pixels closest to the color, from 0 to 1.
+}
+
+// Hex returns the color as "#rrggbb".
+func (s Swatch) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", s.Color.R, s.Color.G, s.Color.B)
+}
+
+// ExtractPalette returns at most n dominant colors of the opaque pixels of img, heaviest first.
+func ExtractPalette(img image.Image, n int, method PaletteMethod) ([]Swatch, error) {
+	if n < 1 || n > 256 {
+		return nil, fmt.Errorf("palette size %d is outside 1 to 256", n)
+	}
+
+	colors := medianCut(img, n)
+	if len(colors) == 0 {
+		return nil, fmt.Errorf("image has no opaque pixels")
+	}
+	if method == KMeans {
+		colors = kMeans(samplePixels(img), colors)
+	}
+
+	var total int
+	for _, c := range colors {
+		total += c.Count
+	}
+	swatches := make([]Swatch, 0, len(colors))
+	for _, c := range colors {
+		if c.Count > 0 {
+			swatches = append(swatches, Swatch{c.Color, float64(c.Count) / float64(total)})
+		}
+	}
+	sort.SliceStable(swatches, func(i, j int) bool { return swatches[i].Weight > swatches[j].Weight })
+	return swatches, nil
+}
+
+// ExtractPaletteFile decodes the image at path and returns its dominant colors, see ExtractPalette.
+func ExtractPaletteFile(path string, n int, method PaletteMethod) ([]Swatch, error) {
+	img, err := DecodeFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ExtractPalette(img, n, method)
+}
+
+// kMeans moves the centers to the mean of the pixels closest to them until they settle,
+// and returns them with the number of pixels they stand for.
+func kMeans(pixels [][3]uint8, centers []colorCount) []colorCount {
+	means := make([][3]float64, len(centers))
+	for i, c := range centers {
+		means[i] = [3]float64{float64(c.Color.R), float64(c.Color.G), float64(c.Color.B)}
+	}
+
+	counts := make([]int, len(centers))
+	for iteration := 0; iteration < kMeansIterations; iteration++ {
+		sums := make([][3]float64, len(centers))
+		for i := range counts {
+			counts[i] = 0
+		}
+		for _, p := range pixels {
+			best, bestDistance := 0, -1.0
+			for i, m := range means {
+				dr, dg, db := float64(p[0])-m[0], float64(p[1])-m[1], float64(p[2])-m[2]
+				if d := dr*dr + dg*dg + db*db; bestDistance < 0 || d < bestDistance {
+					best, bestDistance = i, d
+				}
+			}
+			counts[best]++
+			for c := 0; c < 3; c++ {
+				sums[best][c] += float64(p[c])
+			}
+		}
+
+		moved := false
+		for i := range means {
+			if counts[i] == 0 {
+				continue
+			}
+			for c := 0; c < 3; c++ {
+				m := sums[i][c] / float64(counts[i])
+				if m-means[i][c] > 0.5 || means[i][c]-m > 0.5 {
+					moved = true
+				}
+				means[i][c] = m
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+
+	out := make([]colorCount, len(means))
+	for i, m := range means {
+		out[i] = colorCount{color.NRGBA{uint8(m[0] + 0.5), uint8(m[1] + 0.5), uint8(m[2] + 0.5), 255}, counts[i]}
+	}
+	return out
+}
+
+// PaletteHex returns the colors as "#rrggbb" lines.
+func PaletteHex(swatches []Swatch) string {
+	var sb strings.Builder
+	for _, s := range swatches {
+		sb.WriteString(s.Hex() + "\n")
+	}
+	return sb.String()
+}
+
+// paletteJSONColor is a swatch as written by PaletteJSON.
+type paletteJSONColor struct {
+	Hex    string   `json:"hex"`
+	RGB    [3]uint8 `json:"rgb"`
+	Weight float64  `json:"weight"`
+}
+
+// PaletteJSON returns the colors as a JSON array of hex, rgb and weight objects.
+func PaletteJSON(swatches []Swatch) ([]byte, error) {
+	colors := make([]paletteJSONColor, len(swatches))
+	for i, s := range swatches {
+		colors[i] = paletteJSONColor{s.Hex(), [3]uint8{s.Color.R, s.Color.G, s.Color.B}, s.Weight}
+	}
+	return json.MarshalIndent(colors, "", "  ")
+}
+
+// PaletteCSS returns the colors as CSS custom properties on :root, named "--<prefix>-1" onwards.
+func PaletteCSS(swatches []Swatch, prefix string) string {
+	if prefix == "" {
+		prefix = "color"
+	}
+	var sb strings.Builder
+	sb.WriteString(":root {\n")
+	for i, s := range swatches {
+		fmt.Fprintf(&sb, "  --%s-%d: %s;\n", prefix, i+1, s.Hex())
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// WritePalette writes the colors to path as JSON for ".json", CSS variables for ".css"
+// and hex lines otherwise.
+func WritePalette(path string, swatches []Swatch) error {
+	var data []byte
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var err error
+		if data, err = PaletteJSON(swatches); err != nil {
+			return err
+		}
+	case ".css":
+		data = []byte(PaletteCSS(swatches, ""))
+	default:
+		data = []byte(PaletteHex(swatches))
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,85 @@
+package image_convert
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// logoImage is three quarters dark blue and one quarter orange, with shades of each.
+func logoImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 40))
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			shade := uint8(x % 4)
+			c := color.NRGBA{0x10 + shade, 0x20 + shade, 0x80 + shade, 255}
+			if y >= 30 {
+				c = color.NRGBA{0xf0 - shade, 0x80 + shade, 0x10, 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestExtractPalette(t *testing.T) {
+	for _, method := range PaletteMethods {
+		swatches, err := ExtractPalette(logoImage(), 2, method)
+		if !assert.NoError(t, err, method) || !assert.Len(t, swatches, 2, method) {
+			continue
+		}
+
+		assert.InDelta(t, 0.75, swatches[0].Weight, 0.001, method)
+		assert.InDelta(t, 0.25, swatches[1].Weight, 0.001, method)
+		assert.InDelta(t, 0x80, int(swatches[0].Color.B), 2, method)
+		assert.InDelta(t, 0xf0, int(swatches[1].Color.R), 2, method)
+	}
+}
+
+func TestExtractPaletteInvalid(t *testing.T) {
+	_, err := ExtractPalette(logoImage(), 0, MedianCut)
+	assert.Error(t, err)
+	_, err = ExtractPalette(image.NewNRGBA(image.Rect(0, 0, 4, 4)), 4, KMeans)
+	assert.Error(t, err, "transparent images have no colors")
+}
+
+func TestKMeansRefinesCenters(t *testing.T) {
+	pixels := [][3]uint8{{0, 0, 0}, {10, 10, 10}, {200, 200, 200}, {210, 210, 210}}
+	centers := []colorCount{{color.NRGBA{A: 255}, 0}, {color.NRGBA{R: 100, G: 100, B: 100, A: 255}, 0}}
+	assert.Equal(t, []colorCount{
+		{color.NRGBA{5, 5, 5, 255}, 2},
+		{color.NRGBA{205, 205, 205, 255}, 2},
+	}, kMeans(pixels, centers))
+}
+
+func TestPaletteExports(t *testing.T) {
+	swatches := []Swatch{
+		{color.NRGBA{0x12, 0x34, 0x56, 255}, 0.75},
+		{color.NRGBA{0xff, 0x80, 0x00, 255}, 0.25},
+	}
+
+	assert.Equal(t, "#123456\n#ff8000\n", PaletteHex(swatches))
+	assert.Equal(t, ":root {\n  --brand-1: #123456;\n  --brand-2: #ff8000;\n}\n", PaletteCSS(swatches, "brand"))
+
+	data, err := PaletteJSON(swatches)
+	assert.NoError(t, err)
+	var decoded []map[string]any
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "#123456", decoded[0]["hex"])
+	assert.Equal(t, []any{18.0, 52.0, 86.0}, decoded[0]["rgb"])
+	assert.Equal(t, 0.25, decoded[1]["weight"])
+
+	dir := t.TempDir()
+	for name, want := range map[string]string{"p.css": ":root {", "p.json": "[", "p.txt": "#123456"} {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, WritePalette(path, swatches))
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), want, name)
+	}
+}
@@ -0,0 +1,136 @@
+package image_convert
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+// maxQuantizeSamples is the largest number of pixels sampled when building a palette.
+const maxQuantizeSamples = 1 << 18
+
+// colorCount is a color with the number of sampled pixels it stands for.
+type colorCount struct {
+	Color color.NRGBA
+	Count int
+}
+
+// colorBox is a set of sampled pixels split by medianCut.
+type colorBox struct {
+	pixels [][3]uint8
+}
+
+// medianCut returns at most n colors standing for the opaque pixels of img, most frequent first.
+// It repeatedly splits the box with the widest channel range along that channel, see splitIndex.
+func medianCut(img image.Image, n int) []colorCount {
+	pixels := samplePixels(img)
+	if len(pixels) == 0 || n <= 0 {
+		return nil
+	}
+
+	boxes := []colorBox{{pixels}}
+	for len(boxes) < n {
+		// Split the box whose widest channel range, weighted by its size, is the largest.
+		best, bestScore, bestChannel := -1, 0, 0
+		for i, box := range boxes {
+			channel, spread := box.widestChannel()
+			if score := spread * len(box.pixels); len(box.pixels) > 1 && spread > 0 && score > bestScore {
+				best, bestScore, bestChannel = i, score, channel
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		box := boxes[best]
+		sort.Slice(box.pixels, func(i, j int) bool { return box.pixels[i][bestChannel] < box.pixels[j][bestChannel] })
+		split := splitIndex(box.pixels, bestChannel)
+		boxes[best] = colorBox{box.pixels[:split]}
+		boxes = append(boxes, colorBox{box.pixels[split:]})
+	}
+
+	colors := make([]colorCount, len(boxes))
+	for i, box := range boxes {
+		colors[i] = colorCount{box.mean(), len(box.pixels)}
+	}
+	sort.SliceStable(colors, func(i, j int) bool { return colors[i].Count > colors[j].Count })
+	return colors
+}
+
+// splitIndex returns the index splitting pixels sorted by channel into the two groups whose
+// means are the furthest apart, weighted by their sizes. Unlike the median, it never splits
+// pixels of the same value and keeps a large cluster whole next to a small one.
+func splitIndex(pixels [][3]uint8, channel int) int {
+	var total float64
+	for _, p := range pixels {
+		total += float64(p[channel])
+	}
+
+	best, bestScore := len(pixels)/2, -1.0
+	var sum float64
+	n := float64(len(pixels))
+	for i := 1; i < len(pixels); i++ {
+		sum += float64(pixels[i-1][channel])
+		if pixels[i][channel] == pixels[i-1][channel] {
+			continue
+		}
+		w0, w1 := float64(i), n-float64(i)
+		d := sum/w0 - (total-sum)/w1
+		if score := w0 * w1 * d * d; score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// samplePixels returns the opaque pixels of img, skipping rows and columns evenly on large images.
+func samplePixels(img image.Image) [][3]uint8 {
+	b := img.Bounds()
+	step := 1
+	for (b.Dx()/step)*(b.Dy()/step) > maxQuantizeSamples {
+		step++
+	}
+
+	var pixels [][3]uint8
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A < 128 {
+				continue
+			}
+			pixels = append(pixels, [3]uint8{c.R, c.G, c.B})
+		}
+	}
+	return pixels
+}
+
+// widestChannel returns the channel with the largest range in the box, and that range.
+func (b colorBox) widestChannel() (int, int) {
+	lo, hi := [3]uint8{255, 255, 255}, [3]uint8{}
+	for _, p := range b.pixels {
+		for c := 0; c < 3; c++ {
+			lo[c] = min(lo[c], p[c])
+			hi[c] = max(hi[c], p[c])
+		}
+	}
+
+	channel, spread := 0, 0
+	for c := 0; c < 3; c++ {
+		if s := int(hi[c]) - int(lo[c]); s > spread {
+			channel, spread = c, s
+		}
+	}
+	return channel, spread
+}
+
+// mean returns the average color of the box.
+func (b colorBox) mean() color.NRGBA {
+	var sum [3]int
+	for _, p := range b.pixels {
+		for c := 0; c < 3; c++ {
+			sum[c] += int(p[c])
+		}
+	}
+	n := max(1, len(b.pixels))
+	return color.NRGBA{uint8((sum[0] + n/2) / n), uint8((sum[1] + n/2) / n), uint8((sum[2] + n/2) / n), 255}
+}
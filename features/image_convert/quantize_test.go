@@ -0,0 +1,32 @@
+package image_convert
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMedianCut(t *testing.T) {
+	// Three quarters blue, one quarter green, with a transparent column that is ignored.
+	img := image.NewNRGBA(image.Rect(0, 0, 5, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			c := color.NRGBA{B: 255, A: 255}
+			if y == 3 {
+				c = color.NRGBA{G: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+
+	colors := medianCut(img, 4)
+	assert.Equal(t, []colorCount{
+		{color.NRGBA{B: 255, A: 255}, 12},
+		{color.NRGBA{G: 255, A: 255}, 4},
+	}, colors, "boxes with a single color are not split")
+
+	assert.Len(t, medianCut(img, 1), 1)
+	assert.Nil(t, medianCut(image.NewNRGBA(image.Rect(0, 0, 2, 2)), 4))
+}
@@ -0,0 +1,174 @@
+package image_convert
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// Filter is the resampling filter used when resizing images.
+type Filter int
+
+const (
+	Lanczos Filter = iota
+	CatmullRom
+	Bilinear
+	Nearest
+)
+
+// Filters lists every supported filter, from the highest to the lowest quality.
+var Filters = []Filter{Lanczos, CatmullRom, Bilinear, Nearest}
+
+// String returns the name of the filter.
+func (f Filter) String() string {
+	switch f {
+	case CatmullRom:
+		return "catmullrom"
+	case Bilinear:
+		return "bilinear"
+	case Nearest:
+		return "nearest"
+	default:
+		return "lanczos"
+	}
+}
+
+// Next returns the filter after f in Filters, wrapping around.
+func (f Filter) Next() Filter {
+	return Filters[(int(f)+1)%len(Filters)]
+}
+
+// ParseFilter returns the filter with the given name.
+func ParseFilter(name string) (Filter, error) {
+	for _, f := range Filters {
+		if strings.EqualFold(name, f.String()) {
+			return f, nil
+		}
+	}
+	return Lanczos, fmt.Errorf("unknown filter %q", name)
+}
+
+// lanczos3 is the Lanczos kernel with a support of 3, which is not part of x/image/draw.
+var lanczos3 = &draw.Kernel{Support: 3, At: func(t float64) float64 {
+	if t == 0 {
+		return 1
+	}
+	if t < 0 {
+		t = -t
+	}
+	if t >= 3 {
+		return 0
+	}
+	x := math.Pi * t
+	return 3 * math.Sin(x) * math.Sin(x/3) / (x * x)
+}}
+
+// Interpolator returns the x/image/draw interpolator for the filter.
+func (f Filter) Interpolator() draw.Interpolator {
+	switch f {
+	case CatmullRom:
+		return draw.CatmullRom
+	case Bilinear:
+		return draw.BiLinear
+	case Nearest:
+		return draw.NearestNeighbor
+	default:
+		return lanczos3
+	}
+}
+
+// ResizeOptions controls how images are resized.
+type ResizeOptions struct {
+	Filter     Filter
+	Mode       ResizeMode
+	Crop       CropAnchor  // Part of the image kept in Cover mode.
+	Background color.Color // Padding color in Contain mode, nil for transparent.
+
+	// SharpenMaxSize applies an unsharp mask to outputs whose width and height are
+	// at most this size, 0 to disable. Small icons lose detail when downscaled.
+	SharpenMaxSize int
+	SharpenAmount  float64 // Strength of the unsharp mask, 0.5 is a good start.
+}
+
+// DefaultResizeOptions is used when no resize options are given.
+var DefaultResizeOptions = ResizeOptions{
+	Filter:        Lanczos,
+	SharpenAmount: 0.5,
+}
+
+// Resize scales img to size, fitting it according to the resize mode. Large downscales
+// are done in halving steps before the final pass, which avoids aliasing with the simpler filters.
+func Resize(img image.Image, size image.Point, opts ResizeOptions) *image.RGBA {
+	src := img
+	sr, dr := FitBounds(img, size, opts)
+	if opts.Filter != Nearest {
+		for {
+			b := sr.Size()
+			if b.X < dr.Dx()*2 || b.Y < dr.Dy()*2 {
+				break
+			}
+			half := image.NewRGBA(image.Rect(0, 0, b.X/2, b.Y/2))
+			draw.BiLinear.Scale(half, half.Bounds(), src, sr, draw.Src, nil)
+			src, sr = half, half.Bounds()
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	op := draw.Src
+	if opts.Background != nil {
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(opts.Background), image.Point{}, draw.Src)
+		op = draw.Over
+	}
+	opts.Filter.Interpolator().Scale(dst, dr, src, sr, op, nil)
+
+	if opts.SharpenMaxSize > 0 && size.X <= opts.SharpenMaxSize && size.Y <= opts.SharpenMaxSize {
+		dst = UnsharpMask(dst, opts.SharpenAmount)
+	}
+
+	return dst
+}
+
+// UnsharpMask sharpens img by adding the difference to a blurred copy, scaled by amount.
+// The blur is a 3x3 Gaussian, which suits icon sized images. Alpha is left unchanged.
+func UnsharpMask(img *image.RGBA, amount float64) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+	copy(out.Pix, img.Pix)
+
+	weights := [3]float64{1, 2, 1}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			var blur [3]float64
+			var total float64
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					p := image.Pt(x+dx, y+dy)
+					if !p.In(b) {
+						continue
+					}
+					w := weights[dx+1] * weights[dy+1]
+					i := img.PixOffset(p.X, p.Y)
+					for c := 0; c < 3; c++ {
+						blur[c] += w * float64(img.Pix[i+c])
+					}
+					total += w
+				}
+			}
+
+			i := img.PixOffset(x, y)
+			alpha := float64(img.Pix[i+3])
+			for c := 0; c < 3; c++ {
+				v := float64(img.Pix[i+c])
+				v += amount * (v - blur[c]/total)
+				// Premultiplied color channels can't exceed alpha.
+				out.Pix[i+c] = uint8(math.Round(math.Max(0, math.Min(alpha, v))))
+			}
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,56 @@
+package image_convert
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilter(t *testing.T) {
+	for _, f := range Filters {
+		parsed, err := ParseFilter(f.String())
+		assert.NoError(t, err)
+		assert.Equal(t, f, parsed)
+	}
+
+	_, err := ParseFilter("bicubic")
+	assert.Error(t, err)
+	assert.Equal(t, Lanczos, Nearest.Next())
+}
+
+func TestResize(t *testing.T) {
+	// A fine checkerboard should average out to grey instead of aliasing.
+	src := image.NewRGBA(image.Rect(0, 0, 512, 512))
+	for y := 0; y < 512; y++ {
+		for x := 0; x < 512; x++ {
+			if (x+y)%2 == 0 {
+				src.Set(x, y, color.White)
+			} else {
+				src.Set(x, y, color.Black)
+			}
+		}
+	}
+
+	for _, f := range []Filter{Lanczos, CatmullRom, Bilinear} {
+		dst := Resize(src, image.Pt(16, 16), ResizeOptions{Filter: f})
+		assert.Equal(t, image.Rect(0, 0, 16, 16), dst.Bounds())
+		r := dst.RGBAAt(8, 8).R
+		assert.InDelta(t, 128, int(r), 16, "filter %s", f)
+	}
+}
+
+func TestUnsharpMask(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 1))
+	for x, v := range []uint8{100, 100, 200, 200} {
+		img.SetRGBA(x, 0, color.RGBA{v, v, v, 255})
+	}
+
+	sharp := UnsharpMask(img, 1)
+
+	// The edge gets more contrast, flat areas stay the same.
+	assert.Less(t, sharp.RGBAAt(1, 0).R, uint8(100))
+	assert.Greater(t, sharp.RGBAAt(2, 0).R, uint8(200))
+	assert.Equal(t, uint8(255), sharp.RGBAAt(1, 0).A)
+}
@@ -0,0 +1,228 @@
+package image_convert
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// MaxTextIconLength is the largest number of characters drawn on a text icon. Characters are
+// grapheme clusters, so an emoji sequence like a flag counts as one.
+const MaxTextIconLength = 3
+
+// ErrMissingGlyph is returned when the font of a text icon can't draw one of its characters.
+var ErrMissingGlyph = errors.New("font has no glyph")
+
+// TextIconShape is the shape drawn behind the text of a text icon.
+type TextIconShape int
+
+const (
+	ShapeCircle TextIconShape = iota
+	ShapeRoundedSquare
+	ShapeSquare
+)
+
+// TextIconShapes lists every text icon shape.
+var TextIconShapes = []TextIconShape{ShapeCircle, ShapeRoundedSquare, ShapeSquare}
+
+// String returns the name of the shape.
+func (s TextIconShape) String() string {
+	switch s {
+	case ShapeRoundedSquare:
+		return "rounded"
+	case ShapeSquare:
+		return "square"
+	default:
+		return "circle"
+	}
+}
+
+// Next returns the shape after s in TextIconShapes, wrapping around.
+func (s TextIconShape) Next() TextIconShape {
+	return TextIconShapes[(int(s)+1)%len(TextIconShapes)]
+}
+
+// ParseTextIconShape returns the shape with the given name.
+func ParseTextIconShape(name string) (TextIconShape, error) {
+	for _, s := range TextIconShapes {
+		if strings.EqualFold(name, s.String()) {
+			return s, nil
+		}
+	}
+	return ShapeCircle, fmt.Errorf("unknown shape %q", name)
+}
+
+// radius returns the corner radius of the shape, as a fraction of its size.
+func (s TextIconShape) radius() float64 {
+	switch s {
+	case ShapeRoundedSquare:
+		return 0.2
+	case ShapeSquare:
+		return 0
+	default:
+		return 0.5
+	}
+}
+
+// TextIconOptions configures TextIcon.
+type TextIconOptions struct {
+	Text       string        // 1 to MaxTextIconLength characters.
+	Shape      TextIconShape // Shape behind the text.
+	Background color.Color   // Color of the shape, picked from the text if nil.
+	Foreground color.Color   // Color of the text, white if nil.
+	Size       int           // Width and height of the icon, 1024 if 0.
+
+	// Font is an OpenType or TrueType font, Go Bold if nil. Go Bold has no emoji, so they need
+	// a monochrome outline emoji font, drawn in the foreground color. The text isn't shaped:
+	// sequences the font joins into one glyph, like flags, are drawn as their parts.
+	Font []byte
+}
+
+// textIconColors are the backgrounds picked from the text when none is given.
+var textIconColors = []color.NRGBA{
+	{0xe5, 0x39, 0x35, 0xff}, // Red.
+	{0xd8, 0x1b, 0x60, 0xff}, // Pink.
+	{0x8e, 0x24, 0xaa, 0xff}, // Purple.
+	{0x39, 0x49, 0xab, 0xff}, // Indigo.
+	{0x1e, 0x88, 0xe5, 0xff}, // Blue.
+	{0x00, 0x89, 0x7b, 0xff}, // Teal.
+	{0x43, 0xa0, 0x47, 0xff}, // Green.
+	{0xf4, 0x51, 0x1e, 0xff}, // Orange.
+	{0x6d, 0x4c, 0x41, 0xff}, // Brown.
+	{0x54, 0x6e, 0x7a, 0xff}, // Blue grey.
+}
+
+// TextIconColor returns the background picked for text, the same text always getting the same color.
+func TextIconColor(text string) color.NRGBA {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(text)))
+	return textIconColors[h.Sum32()%uint32(len(textIconColors))]
+}
+
+// TextIcon draws a letter avatar: the text centered on a shape, scaled to fill about two thirds of it.
+func TextIcon(opts TextIconOptions) (*image.RGBA, error) {
+	text := strings.TrimSpace(opts.Text)
+	if n := uniseg.GraphemeClusterCount(text); n == 0 || n > MaxTextIconLength {
+		return nil, fmt.Errorf("text icons need 1 to %d characters, got %q", MaxTextIconLength, opts.Text)
+	}
+	size := opts.Size
+	if size == 0 {
+		size = effectCanvasSize
+	}
+	background := opts.Background
+	if background == nil {
+		background = TextIconColor(text)
+	}
+	foreground := opts.Foreground
+	if foreground == nil {
+		foreground = color.White
+	}
+	data := opts.Font
+	if data == nil {
+		data = gobold.TTF
+	}
+
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing font: %w", err)
+	}
+	text = strings.Map(dropJoiner, text)
+	if text == "" {
+		return nil, fmt.Errorf("text icons need a visible character, got %q", opts.Text)
+	}
+	var buf sfnt.Buffer
+	for _, r := range text {
+		if i, err := f.GlyphIndex(&buf, r); err != nil || i == 0 {
+			if opts.Font == nil {
+				return nil, fmt.Errorf("%w for %q in the default font, set a font that has it", ErrMissingGlyph, r)
+			}
+			return nil, fmt.Errorf("%w for %q", ErrMissingGlyph, r)
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	shape := image.NewAlpha(img.Bounds())
+	s := float64(size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			coverage := roundedRectCoverage(float64(x)+0.5, float64(y)+0.5, s, s, opts.Shape.radius()*s)
+			shape.Pix[shape.PixOffset(x, y)] = uint8(math.Round(coverage * 255))
+		}
+	}
+	draw.DrawMask(img, img.Bounds(), image.NewUniform(background), image.Point{}, shape, image.Point{}, draw.Over)
+
+	if err := drawCenteredText(img, f, text, foreground); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// dropJoiner drops the zero width joiner and the variation selectors of emoji sequences.
+// They only pick how a shaped sequence looks, and fonts without shaping have no glyph for them.
+func dropJoiner(r rune) rune {
+	if r == '\u200d' || (r >= '\ufe00' && r <= '\ufe0f') {
+		return -1
+	}
+	return r
+}
+
+// drawCenteredText draws text on img, with its ink bounds centered and fitting in the middle of img.
+func drawCenteredText(img *image.RGBA, f *opentype.Font, text string, c color.Color) error {
+	size := float64(img.Bounds().Dx())
+	maxWidth, maxHeight := size*0.62, size*0.46
+
+	// Measure at a reference size, then scale to fit.
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size / 2, DPI: 72, Hinting: font.HintingNone})
+	if err != nil {
+		return fmt.Errorf("error loading font: %w", err)
+	}
+	bounds, _ := font.BoundString(face, text)
+	face.Close()
+	w, h := (bounds.Max.X - bounds.Min.X).Round(), (bounds.Max.Y - bounds.Min.Y).Round()
+	if w <= 0 || h <= 0 {
+		return nil
+	}
+	scale := math.Min(maxWidth/float64(w), maxHeight/float64(h))
+
+	face, err = opentype.NewFace(f, &opentype.FaceOptions{Size: size / 2 * scale, DPI: 72, Hinting: font.HintingNone})
+	if err != nil {
+		return fmt.Errorf("error loading font: %w", err)
+	}
+	defer face.Close()
+	bounds, _ = font.BoundString(face, text)
+
+	// Move the dot so the center of the ink bounds is the center of the image.
+	center := fixed.I(int(size)) / 2
+	d := font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot: fixed.Point26_6{
+			X: center - (bounds.Min.X+bounds.Max.X)/2,
+			Y: center - (bounds.Min.Y+bounds.Max.Y)/2,
+		},
+	}
+	d.DrawString(text)
+	return nil
+}
+
+// ConvertTextIcon draws a text icon and writes every output format in opts, like ConvertImage.
+func ConvertTextIcon(textOpts TextIconOptions, opts Options) (*Result, error) {
+	img, err := TextIcon(textOpts)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertImage(img, opts)
+}
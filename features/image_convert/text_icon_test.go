@@ -0,0 +1,100 @@
+package image_convert
+
+import (
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextIcon(t *testing.T) {
+	bg := color.NRGBA{R: 255, A: 255}
+	img, err := TextIcon(TextIconOptions{Text: "AB", Background: bg, Size: 128})
+	if err != nil {
+		t.Fatalf("Failed to draw text icon: %v", err)
+	}
+	assert.Equal(t, image.Rect(0, 0, 128, 128), img.Bounds())
+	assert.Equal(t, uint8(0), img.RGBAAt(2, 2).A, "the corners are outside the circle")
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, img.RGBAAt(64, 8), "the circle is filled with the background")
+
+	// Some pixels around the center are drawn in the foreground.
+	var white int
+	for y := 32; y < 96; y++ {
+		for x := 16; x < 112; x++ {
+			if img.RGBAAt(x, y) == (color.RGBA{255, 255, 255, 255}) {
+				white++
+			}
+		}
+	}
+	assert.Greater(t, white, 100)
+
+	// Nothing is drawn outside the middle of the icon.
+	for x := 0; x < 128; x++ {
+		assert.NotEqual(t, color.RGBA{255, 255, 255, 255}, img.RGBAAt(x, 10))
+	}
+}
+
+func TestTextIconShapes(t *testing.T) {
+	square, err := TextIcon(TextIconOptions{Text: "x", Shape: ShapeSquare, Size: 64})
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(255), square.RGBAAt(0, 0).A)
+
+	rounded, err := TextIcon(TextIconOptions{Text: "x", Shape: ShapeRoundedSquare, Size: 64})
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(0), rounded.RGBAAt(0, 0).A)
+	assert.Equal(t, uint8(255), rounded.RGBAAt(32, 0).A)
+
+	shape, err := ParseTextIconShape("Rounded")
+	assert.NoError(t, err)
+	assert.Equal(t, ShapeRoundedSquare, shape)
+	assert.Equal(t, ShapeCircle, ShapeSquare.Next())
+}
+
+func TestTextIconInvalid(t *testing.T) {
+	_, err := TextIcon(TextIconOptions{Text: " "})
+	assert.Error(t, err)
+	_, err = TextIcon(TextIconOptions{Text: "ABCD"})
+	assert.Error(t, err)
+	_, err = TextIcon(TextIconOptions{Text: "A", Font: []byte("not a font")})
+	assert.Error(t, err)
+}
+
+func TestTextIconEmoji(t *testing.T) {
+	// A heart with the emoji variation selector is one character, drawn with the heart of Go Bold.
+	_, err := TextIcon(TextIconOptions{Text: "\u2665\ufe0f", Size: 64})
+	assert.NoError(t, err)
+
+	// A family joined with zero width joiners is one character, but Go Bold has no emoji.
+	_, err = TextIcon(TextIconOptions{Text: "👨\u200d👩\u200d👧", Size: 64})
+	assert.ErrorIs(t, err, ErrMissingGlyph)
+	assert.ErrorContains(t, err, "default font")
+
+	// Every flag is a pair of regional indicators, counted as one character.
+	_, err = TextIcon(TextIconOptions{Text: "🇯🇵🇫🇷🇩🇪", Size: 64})
+	assert.ErrorIs(t, err, ErrMissingGlyph)
+	_, err = TextIcon(TextIconOptions{Text: "🇯🇵🇫🇷🇩🇪🇮🇹", Size: 64})
+	assert.NotErrorIs(t, err, ErrMissingGlyph)
+	assert.ErrorContains(t, err, "1 to 3 characters")
+
+	_, err = TextIcon(TextIconOptions{Text: "\u200d"})
+	assert.Error(t, err)
+}
+
+func TestTextIconColor(t *testing.T) {
+	assert.Equal(t, TextIconColor("ab"), TextIconColor("AB"))
+	img, err := TextIcon(TextIconOptions{Text: "ab", Size: 64})
+	assert.NoError(t, err)
+	assert.Equal(t, TextIconColor("ab"), color.NRGBAModel.Convert(img.At(32, 2)))
+}
+
+func TestConvertTextIcon(t *testing.T) {
+	dir := t.TempDir()
+	opts := DefaultOptions()
+	opts.OutputDir = dir
+	opts.Name = "tool"
+	result, err := ConvertTextIcon(TextIconOptions{Text: "DB"}, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "tool.ico")}, result.Files)
+}
@@ -0,0 +1,335 @@
+package library
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sterben/features/youtube"
+	"strings"
+	"time"
+)
+
+// DefaultDirectory is the directory downloads are written to.
+const DefaultDirectory = "downloads"
+
+// SortField selects the field entries are sorted by.
+type SortField int
+
+const (
+	SortByDate SortField = iota
+	SortByTitle
+	SortBySize
+	SortByDuration
+)
+
+// String returns the display name of the sort field.
+func (f SortField) String() string {
+	switch f {
+	case SortByTitle:
+		return "title"
+	case SortBySize:
+		return "size"
+	case SortByDuration:
+		return "duration"
+	default:
+		return "date"
+	}
+}
+
+// Next returns the sort field that follows f, wrapping around.
+func (f SortField) Next() SortField {
+	return (f + 1) % (SortByDuration + 1)
+}
+
+// Entry is a single media file in the download directory.
+type Entry struct {
+	Path     string                 // Path of the media file.
+	Title    string                 // Title from the metadata, or the file name.
+	ID       string                 // Video ID from the metadata, empty if unknown.
+	Size     int64                  // File size in bytes.
+	ModTime  time.Time              // Last modification time of the file.
+	Duration time.Duration          // Duration from the metadata, zero if unknown.
+	Archived bool                   // True if the video is listed in the download archive of its directory.
+	MetaData *youtube.VideoMetaData // Metadata from the .info.json sidecar, nil if missing.
+}
+
+// Library is an index of the media files in a download directory.
+type Library struct {
+	Directory string
+	Entries   []Entry
+
+	archives map[string]map[string]bool // Video IDs in the download archive of each directory, read as needed.
+}
+
+// mediaExtensions lists the file extensions that are indexed as media.
+var mediaExtensions = map[string]bool{
+	".mp4": true, ".mkv": true, ".webm": true, ".mov": true, ".avi": true, ".ts": true,
+	".mp3": true, ".m4a": true, ".opus": true, ".ogg": true, ".flac": true, ".wav": true,
+}
+
+// Index walks dir and returns a library of the media files it contains.
+func Index(dir string) (*Library, error) {
+	l := &Library{
+		Directory: dir,
+		archives:  make(map[string]map[string]bool),
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !IsMedia(path) {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		entry := Entry{
+			Path:    path,
+			Title:   strings.TrimSuffix(d.Name(), filepath.Ext(d.Name())),
+			Size:    fi.Size(),
+			ModTime: fi.ModTime(),
+		}
+
+		if meta, err := readMetaData(path); err == nil {
+			entry.MetaData = meta
+			entry.ID = meta.ID
+			entry.Duration = time.Duration(meta.Duration) * time.Second
+			if meta.Title != "" {
+				entry.Title = meta.Title
+			}
+			archive, err := l.archive(filepath.Dir(path))
+			if err != nil {
+				return err
+			}
+			entry.Archived = archive[meta.ID]
+		}
+
+		l.Entries = append(l.Entries, entry)
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// IsMedia reports whether path has a media file extension.
+func IsMedia(path string) bool {
+	return mediaExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// Sort sorts the entries by field in ascending or descending order.
+func (l *Library) Sort(field SortField, ascending bool) {
+	less := func(a, b Entry) bool {
+		switch field {
+		case SortByTitle:
+			return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+		case SortBySize:
+			return a.Size < b.Size
+		case SortByDuration:
+			return a.Duration < b.Duration
+		default:
+			return a.ModTime.Before(b.ModTime)
+		}
+	}
+
+	sort.SliceStable(l.Entries, func(i, j int) bool {
+		if ascending {
+			return less(l.Entries[i], l.Entries[j])
+		}
+		return less(l.Entries[j], l.Entries[i])
+	})
+}
+
+// Filter returns the entries whose title or path contains query, ignoring case.
+func (l *Library) Filter(query string) []Entry {
+	if query == "" {
+		return l.Entries
+	}
+
+	query = strings.ToLower(query)
+	var entries []Entry
+	for _, entry := range l.Entries {
+		if strings.Contains(strings.ToLower(entry.Title), query) || strings.Contains(strings.ToLower(entry.Path), query) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// TotalSize returns the combined size of all entries.
+func (l *Library) TotalSize() int64 {
+	var size int64
+	for _, entry := range l.Entries {
+		size += entry.Size
+	}
+	return size
+}
+
+// Delete removes the media file of entry together with its sidecars and archive line.
+func (l *Library) Delete(entry Entry) error {
+	if err := os.Remove(entry.Path); err != nil {
+		return err
+	}
+
+	for _, sidecar := range Sidecars(entry.Path) {
+		if err := os.Remove(sidecar); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if entry.ID != "" {
+		dir := filepath.Dir(entry.Path)
+		archive, err := l.archive(dir)
+		if err != nil {
+			return err
+		}
+		if archive[entry.ID] {
+			if err := removeFromArchive(filepath.Join(dir, youtube.ArchiveFileName), entry.ID); err != nil {
+				return fmt.Errorf("error updating archive: %w", err)
+			}
+			delete(archive, entry.ID)
+		}
+	}
+
+	for i := range l.Entries {
+		if l.Entries[i].Path == entry.Path {
+			l.Entries = append(l.Entries[:i], l.Entries[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// Sidecars returns the existing sidecar files written next to a media file.
+func Sidecars(mediaPath string) []string {
+	base := strings.TrimSuffix(mediaPath, filepath.Ext(mediaPath))
+	candidates, _ := filepath.Glob(globEscape(base) + "-poster.*")
+	candidates = append(candidates, base+".info.json", base+".nfo")
+
+	var sidecars []string
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			sidecars = append(sidecars, path)
+		}
+	}
+	return sidecars
+}
+
+// Open opens path in player, or in the system's default application if player is empty.
+func Open(path, player string) error {
+	var cmd *exec.Cmd
+	switch {
+	case player != "":
+		cmd = exec.Command(player, path)
+	case runtime.GOOS == "windows":
+		cmd = exec.Command("cmd", "/c", "start", "", path)
+	case runtime.GOOS == "darwin":
+		cmd = exec.Command("open", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// Reap the player process once it exits.
+	go cmd.Wait()
+	return nil
+}
+
+// readMetaData reads the .info.json sidecar of a media file.
+func readMetaData(mediaPath string) (*youtube.VideoMetaData, error) {
+	data, err := os.ReadFile(strings.TrimSuffix(mediaPath, filepath.Ext(mediaPath)) + ".info.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var meta youtube.VideoMetaData
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// archive returns the video IDs in the download archive of dir, reading it on first use.
+func (l *Library) archive(dir string) (map[string]bool, error) {
+	if archive, ok := l.archives[dir]; ok {
+		return archive, nil
+	}
+	archive, err := readArchive(filepath.Join(dir, youtube.ArchiveFileName))
+	if err != nil {
+		return nil, fmt.Errorf("error reading archive: %w", err)
+	}
+	l.archives[dir] = archive
+	return archive, nil
+}
+
+// readArchive reads the video IDs from a yt-dlp download archive ("<extractor> <id>" per line).
+func readArchive(path string) (map[string]bool, error) {
+	archive := make(map[string]bool)
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return archive, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			archive[fields[1]] = true
+		}
+	}
+
+	return archive, scanner.Err()
+}
+
+// removeFromArchive removes every line for id from the download archive.
+func removeFromArchive(path, id string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || (len(fields) == 2 && fields[1] == id) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	output := strings.Join(lines, "\n")
+	if output != "" {
+		output += "\n"
+	}
+	return os.WriteFile(path, []byte(output), 0644)
+}
+
+// globEscape escapes the glob metacharacters in path.
+func globEscape(path string) string {
+	replacer := strings.NewReplacer("[", "\\[", "]", "\\]", "*", "\\*", "?", "\\?")
+	if runtime.GOOS == "windows" {
+		// Backslashes are path separators on Windows, so brackets are escaped as classes.
+		replacer = strings.NewReplacer("[", "[[]", "*", "[*]", "?", "[?]")
+	}
+	return replacer.Replace(path)
+}
@@ -0,0 +1,111 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"sterben/features/youtube"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestLibrary creates a download directory with two videos, one of them with metadata.
+func writeTestLibrary(t *testing.T) string {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"First.mp4":             "0123456789",
+		"First.info.json":       `{"title":"First Video","id":"aaa","duration":90}`,
+		"First.nfo":             "<episodedetails/>",
+		"First-poster.jpg":      "jpg",
+		"Second.webm":           "01234",
+		"notes.txt":             "not media",
+		youtube.ArchiveFileName: "youtube aaa\nyoutube bbb\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// Make the first video the older one.
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(filepath.Join(dir, "First.mp4"), old, old)
+
+	return dir
+}
+
+func TestIndex(t *testing.T) {
+	l, err := Index(writeTestLibrary(t))
+	if err != nil {
+		t.Fatalf("Failed to index library: %v", err)
+	}
+
+	assert.Len(t, l.Entries, 2)
+	assert.Equal(t, int64(15), l.TotalSize())
+
+	l.Sort(SortBySize, false)
+	assert.Equal(t, "First Video", l.Entries[0].Title)
+	assert.Equal(t, "aaa", l.Entries[0].ID)
+	assert.Equal(t, 90*time.Second, l.Entries[0].Duration)
+	assert.True(t, l.Entries[0].Archived)
+	assert.Equal(t, "Second", l.Entries[1].Title)
+
+	l.Sort(SortByDate, false)
+	assert.Equal(t, "Second", l.Entries[0].Title)
+
+	assert.Len(t, l.Filter("first"), 1)
+	assert.Len(t, l.Filter(""), 2)
+}
+
+func TestDelete(t *testing.T) {
+	dir := writeTestLibrary(t)
+	l, err := Index(dir)
+	if err != nil {
+		t.Fatalf("Failed to index library: %v", err)
+	}
+
+	entry := l.Filter("first")[0]
+	if err := l.Delete(entry); err != nil {
+		t.Fatalf("Failed to delete entry: %v", err)
+	}
+
+	assert.Len(t, l.Entries, 1)
+	assert.NoFileExists(t, filepath.Join(dir, "First.mp4"))
+	assert.NoFileExists(t, filepath.Join(dir, "First.info.json"))
+	assert.NoFileExists(t, filepath.Join(dir, "First.nfo"))
+	assert.NoFileExists(t, filepath.Join(dir, "First-poster.jpg"))
+
+	archive, err := os.ReadFile(filepath.Join(dir, youtube.ArchiveFileName))
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+	assert.Equal(t, "youtube bbb\n", string(archive))
+}
+
+func TestDeletePlaylistArchive(t *testing.T) {
+	dir := t.TempDir()
+	playlistDir := filepath.Join(dir, "Mix")
+	files := map[string]string{
+		filepath.Join(playlistDir, "001 - Song [ccc].mp3"):       "mp3",
+		filepath.Join(playlistDir, "001 - Song [ccc].info.json"): `{"title":"Song","id":"ccc"}`,
+		filepath.Join(playlistDir, youtube.ArchiveFileName):      "youtube ccc\n",
+	}
+	assert.NoError(t, os.MkdirAll(playlistDir, 0755))
+	for path, content := range files {
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	l, err := Index(dir)
+	assert.NoError(t, err)
+	if !assert.Len(t, l.Entries, 1) {
+		return
+	}
+	assert.True(t, l.Entries[0].Archived, "playlists keep their archive next to their files")
+
+	assert.NoError(t, l.Delete(l.Entries[0]))
+	archive, err := os.ReadFile(filepath.Join(playlistDir, youtube.ArchiveFileName))
+	assert.NoError(t, err)
+	assert.Empty(t, string(archive))
+}
@@ -0,0 +1,178 @@
+package playlist
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Format is a playlist file format.
+type Format string
+
+const (
+	M3U8 Format = "m3u8"
+	XSPF Format = "xspf"
+)
+
+// Entry is a single track of a playlist.
+type Entry struct {
+	Path     string        // Path of the media file.
+	Title    string        // Display title, the file name is used if empty.
+	Duration time.Duration // Track duration, zero if unknown.
+}
+
+// xspfPlaylist is the XML Shareable Playlist Format document.
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"playlist"`
+	Version string      `xml:"version,attr"`
+	XMLNS   string      `xml:"xmlns,attr"`
+	Title   string      `xml:"title,omitempty"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+// xspfTrack is a single track of an XSPF playlist.
+type xspfTrack struct {
+	Location string `xml:"location"`
+	Title    string `xml:"title,omitempty"`
+	Duration int64  `xml:"duration,omitempty"` // Milliseconds.
+}
+
+// FormatFromPath returns the playlist format for a file extension.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".m3u8", ".m3u":
+		return M3U8, nil
+	case ".xspf":
+		return XSPF, nil
+	default:
+		return "", fmt.Errorf("unsupported playlist format %q", filepath.Ext(path))
+	}
+}
+
+// Save writes entries to a playlist file, choosing the format from its extension.
+// Entry paths are written relative to the playlist's directory.
+func Save(path, title string, entries []Entry) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+
+	relative, err := Relative(filepath.Dir(path), entries)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch format {
+	case XSPF:
+		return WriteXSPF(f, title, relative)
+	default:
+		return WriteM3U8(f, title, relative)
+	}
+}
+
+// Relative returns a copy of entries with paths relative to dir, using forward slashes.
+func Relative(dir string, entries []Entry) ([]Entry, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	relative := make([]Entry, len(entries))
+	for i, entry := range entries {
+		absPath, err := filepath.Abs(entry.Path)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(absDir, absPath)
+		if err != nil {
+			return nil, err
+		}
+
+		relative[i] = entry
+		relative[i].Path = filepath.ToSlash(rel)
+	}
+	return relative, nil
+}
+
+// WriteM3U8 writes entries as an extended UTF-8 M3U playlist.
+func WriteM3U8(w io.Writer, title string, entries []Entry) error {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	if title != "" {
+		fmt.Fprintf(&b, "#PLAYLIST:%s\n", singleLine(title))
+	}
+
+	for _, entry := range entries {
+		// Unknown durations are written as -1 as specified by the format.
+		seconds := int64(-1)
+		if entry.Duration > 0 {
+			seconds = int64(entry.Duration.Seconds())
+		}
+		fmt.Fprintf(&b, "#EXTINF:%d,%s\n%s\n", seconds, singleLine(entryTitle(entry)), entry.Path)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteXSPF writes entries as an XSPF playlist.
+func WriteXSPF(w io.Writer, title string, entries []Entry) error {
+	doc := xspfPlaylist{
+		Version: "1",
+		XMLNS:   "http://xspf.org/ns/0/",
+		Title:   title,
+	}
+
+	for _, entry := range entries {
+		doc.Tracks = append(doc.Tracks, xspfTrack{
+			Location: locationURI(entry.Path),
+			Title:    entryTitle(entry),
+			Duration: entry.Duration.Milliseconds(),
+		})
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// entryTitle returns the entry title, falling back to the file name without extension.
+func entryTitle(entry Entry) string {
+	if entry.Title != "" {
+		return entry.Title
+	}
+	name := filepath.Base(entry.Path)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// locationURI escapes a slash separated path for use as an XSPF location.
+func locationURI(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// singleLine replaces line breaks so a value fits on a single M3U line.
+func singleLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
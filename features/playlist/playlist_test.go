@@ -0,0 +1,62 @@
+package playlist
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testEntries = []Entry{
+	{Path: "downloads/Mix/001 - First [aaa].mp4", Title: "First", Duration: 61 * time.Second},
+	{Path: "downloads/Mix/002 - Second [bbb].webm"},
+}
+
+func TestWriteM3U8(t *testing.T) {
+	var b strings.Builder
+	if err := WriteM3U8(&b, "Mix", testEntries); err != nil {
+		t.Fatalf("Failed to write m3u8: %v", err)
+	}
+
+	assert.Equal(t, "#EXTM3U\n"+
+		"#PLAYLIST:Mix\n"+
+		"#EXTINF:61,First\n"+
+		"downloads/Mix/001 - First [aaa].mp4\n"+
+		"#EXTINF:-1,002 - Second [bbb]\n"+
+		"downloads/Mix/002 - Second [bbb].webm\n", b.String())
+}
+
+func TestWriteXSPF(t *testing.T) {
+	var b strings.Builder
+	if err := WriteXSPF(&b, "Mix", testEntries); err != nil {
+		t.Fatalf("Failed to write xspf: %v", err)
+	}
+
+	xspf := b.String()
+	assert.Contains(t, xspf, `<playlist version="1" xmlns="http://xspf.org/ns/0/">`)
+	assert.Contains(t, xspf, "<location>downloads/Mix/001%20-%20First%20%5Baaa%5D.mp4</location>")
+	assert.Contains(t, xspf, "<duration>61000</duration>")
+}
+
+func TestSaveRelative(t *testing.T) {
+	dir := t.TempDir()
+	entries := []Entry{{Path: filepath.Join(dir, "Mix", "001.mp4"), Title: "First"}}
+
+	path := filepath.Join(dir, "Mix", "Mix.m3u8")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := Save(path, "Mix", entries); err != nil {
+		t.Fatalf("Failed to save playlist: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read playlist: %v", err)
+	}
+	assert.Contains(t, string(data), "\n001.mp4\n")
+
+	_, err = FormatFromPath("playlist.pls")
+	assert.Error(t, err)
+}
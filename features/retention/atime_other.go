@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !windows
+
+package retention
+
+import (
+	"os"
+	"time"
+)
+
+// accessTime returns the last modification time, access times are not read on this platform.
+func accessTime(fi os.FileInfo) time.Time {
+	return fi.ModTime()
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package retention
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// accessTime returns the last access time of a file.
+func accessTime(fi os.FileInfo) time.Time {
+	if data, ok := fi.Sys().(*syscall.Win32FileAttributeData); ok {
+		return time.Unix(0, data.LastAccessTime.Nanoseconds())
+	}
+	return fi.ModTime()
+}
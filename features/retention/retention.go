@@ -0,0 +1,193 @@
+package retention
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sterben/features/library"
+	"sterben/pkg/log"
+	"time"
+)
+
+// Strategy selects which files are removed first when the directory is over quota.
+type Strategy string
+
+const (
+	OldestFirst       Strategy = "oldest" // Remove the files downloaded first.
+	LeastRecentlyUsed Strategy = "lru"    // Remove the files accessed least recently.
+)
+
+// Policy describes the limits enforced on a download directory.
+type Policy struct {
+	MaxTotalSize int64         // Maximum combined size in bytes, zero for no limit.
+	MaxAge       time.Duration // Maximum age of a file, zero for no limit.
+	Keep         []string      // Pinned files, matched against path, file name (glob) or video ID.
+	Strategy     Strategy      // Order in which files are removed when over quota.
+}
+
+// NewPolicy returns the policy for limits given in megabytes and days, as in the config.
+// An unknown strategy falls back to OldestFirst.
+func NewPolicy(maxTotalSizeMB, maxAgeDays int, keep []string, strategy string) Policy {
+	s := OldestFirst
+	if Strategy(strategy) == LeastRecentlyUsed {
+		s = LeastRecentlyUsed
+	}
+
+	return Policy{
+		MaxTotalSize: int64(maxTotalSizeMB) * 1024 * 1024,
+		MaxAge:       time.Duration(maxAgeDays) * 24 * time.Hour,
+		Keep:         keep,
+		Strategy:     s,
+	}
+}
+
+// Enabled reports whether the policy has a size or age limit.
+func (p Policy) Enabled() bool {
+	return p.MaxTotalSize > 0 || p.MaxAge > 0
+}
+
+// Reason explains why a file is scheduled for deletion.
+type Reason string
+
+const (
+	ReasonMaxAge   Reason = "older than max age"
+	ReasonMaxTotal Reason = "over size quota"
+)
+
+// Deletion is a single file scheduled for deletion.
+type Deletion struct {
+	Entry  library.Entry
+	Reason Reason
+}
+
+// Report is the result of planning a cleanup, it can be shown as a dry run or applied.
+type Report struct {
+	Deletions   []Deletion
+	Pinned      int   // Number of files skipped because they are pinned.
+	TotalBefore int64 // Combined size before the cleanup.
+	TotalAfter  int64 // Combined size after the cleanup.
+}
+
+// Freed returns the number of bytes the cleanup frees.
+func (r *Report) Freed() int64 {
+	return r.TotalBefore - r.TotalAfter
+}
+
+// Plan returns the files that have to be deleted for lib to satisfy policy at time now.
+// Nothing is deleted, use Apply to carry out the report.
+func Plan(lib *library.Library, policy Policy, now time.Time) *Report {
+	report := &Report{
+		TotalBefore: lib.TotalSize(),
+		TotalAfter:  lib.TotalSize(),
+	}
+
+	var candidates []library.Entry
+	for _, entry := range lib.Entries {
+		if IsPinned(entry, policy.Keep) {
+			report.Pinned++
+			continue
+		}
+
+		if policy.MaxAge > 0 && now.Sub(entry.ModTime) > policy.MaxAge {
+			report.Deletions = append(report.Deletions, Deletion{Entry: entry, Reason: ReasonMaxAge})
+			report.TotalAfter -= entry.Size
+			continue
+		}
+
+		candidates = append(candidates, entry)
+	}
+
+	if policy.MaxTotalSize <= 0 || report.TotalAfter <= policy.MaxTotalSize {
+		return report
+	}
+
+	// Remove files in strategy order until the directory fits the quota.
+	sortByStrategy(candidates, policy.Strategy)
+	for _, entry := range candidates {
+		if report.TotalAfter <= policy.MaxTotalSize {
+			break
+		}
+		report.Deletions = append(report.Deletions, Deletion{Entry: entry, Reason: ReasonMaxTotal})
+		report.TotalAfter -= entry.Size
+	}
+
+	return report
+}
+
+// Apply deletes every file in report from lib, logging each deletion. It continues
+// past failures and returns the first error encountered.
+func Apply(lib *library.Library, report *Report, l log.Log) error {
+	var firstErr error
+	for _, deletion := range report.Deletions {
+		if err := lib.Delete(deletion.Entry); err != nil {
+			l.Error().Err(err).Str("path", deletion.Entry.Path).Msg("Failed to delete file")
+			if firstErr == nil {
+				firstErr = fmt.Errorf("error deleting %s: %w", deletion.Entry.Path, err)
+			}
+			continue
+		}
+
+		l.Info().
+			Str("path", deletion.Entry.Path).
+			Int64("size", deletion.Entry.Size).
+			Str("reason", string(deletion.Reason)).
+			Msg("Deleted file")
+	}
+
+	return firstErr
+}
+
+// Enforce indexes dir and deletes the files policy doesn't allow at time now, like Plan
+// followed by Apply. Nothing is indexed when the policy isn't enabled, and the report is nil.
+func Enforce(dir string, policy Policy, now time.Time, l log.Log) (*Report, error) {
+	if !policy.Enabled() {
+		return nil, nil
+	}
+
+	lib, err := library.Index(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error indexing %s: %w", dir, err)
+	}
+
+	report := Plan(lib, policy, now)
+	return report, Apply(lib, report, l)
+}
+
+// IsPinned reports whether entry matches one of the keep patterns.
+func IsPinned(entry library.Entry, keep []string) bool {
+	name := filepath.Base(entry.Path)
+	for _, pattern := range keep {
+		if pattern == entry.Path || pattern == name || (entry.ID != "" && pattern == entry.ID) {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sortByStrategy orders entries so the first entry is the first to be removed.
+func sortByStrategy(entries []library.Entry, strategy Strategy) {
+	key := func(entry library.Entry) time.Time {
+		return entry.ModTime
+	}
+
+	if strategy == LeastRecentlyUsed {
+		accessTimes := make(map[string]time.Time, len(entries))
+		for _, entry := range entries {
+			accessTimes[entry.Path] = entry.ModTime
+			if fi, err := os.Stat(entry.Path); err == nil {
+				accessTimes[entry.Path] = accessTime(fi)
+			}
+		}
+		key = func(entry library.Entry) time.Time {
+			return accessTimes[entry.Path]
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return key(entries[i]).Before(key(entries[j]))
+	})
+}
@@ -0,0 +1,74 @@
+package retention
+
+import (
+	"sterben/features/library"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+
+// testLibrary returns an in-memory library with files of 100 bytes, one per day.
+func testLibrary() *library.Library {
+	return &library.Library{
+		Entries: []library.Entry{
+			{Path: "downloads/a.mp4", Size: 100, ModTime: now.AddDate(0, 0, -1)},
+			{Path: "downloads/b.mp4", Size: 100, ModTime: now.AddDate(0, 0, -2)},
+			{Path: "downloads/c.mp4", Size: 100, ModTime: now.AddDate(0, 0, -3), ID: "ccc"},
+			{Path: "downloads/d.mp4", Size: 100, ModTime: now.AddDate(0, 0, -40)},
+		},
+	}
+}
+
+func TestPlanMaxAge(t *testing.T) {
+	report := Plan(testLibrary(), Policy{MaxAge: 30 * 24 * time.Hour}, now)
+
+	assert.Len(t, report.Deletions, 1)
+	assert.Equal(t, "downloads/d.mp4", report.Deletions[0].Entry.Path)
+	assert.Equal(t, ReasonMaxAge, report.Deletions[0].Reason)
+	assert.Equal(t, int64(100), report.Freed())
+}
+
+func TestPlanMaxTotalSizeOldestFirst(t *testing.T) {
+	report := Plan(testLibrary(), Policy{
+		MaxTotalSize: 200,
+		Keep:         []string{"ccc"},
+		Strategy:     OldestFirst,
+	}, now)
+
+	// c.mp4 is older than b.mp4 but pinned by its video ID.
+	assert.Len(t, report.Deletions, 2)
+	assert.Equal(t, "downloads/d.mp4", report.Deletions[0].Entry.Path)
+	assert.Equal(t, "downloads/b.mp4", report.Deletions[1].Entry.Path)
+	assert.Equal(t, ReasonMaxTotal, report.Deletions[1].Reason)
+	assert.Equal(t, 1, report.Pinned)
+	assert.Equal(t, int64(200), report.TotalAfter)
+}
+
+func TestIsPinned(t *testing.T) {
+	entry := library.Entry{Path: "downloads/Channel/video [abc].mp4", ID: "abc"}
+
+	assert.True(t, IsPinned(entry, []string{"abc"}))
+	assert.True(t, IsPinned(entry, []string{"video*"}))
+	assert.True(t, IsPinned(entry, []string{"downloads/Channel/video [abc].mp4"}))
+	assert.False(t, IsPinned(entry, []string{"other"}))
+}
+
+func TestNewPolicy(t *testing.T) {
+	policy := NewPolicy(2, 30, []string{"ccc"}, "lru")
+	assert.Equal(t, int64(2*1024*1024), policy.MaxTotalSize)
+	assert.Equal(t, 30*24*time.Hour, policy.MaxAge)
+	assert.Equal(t, LeastRecentlyUsed, policy.Strategy)
+	assert.True(t, policy.Enabled())
+
+	policy = NewPolicy(0, 0, nil, "newest")
+	assert.Equal(t, OldestFirst, policy.Strategy)
+	assert.False(t, policy.Enabled())
+
+	// Without limits nothing is indexed, so a missing directory is no error.
+	report, err := Enforce("missing", policy, now, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, report)
+}
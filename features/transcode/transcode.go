@@ -0,0 +1,251 @@
+package transcode
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Preset describes a named ffmpeg transcoding profile.
+type Preset struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Extension   string   `json:"extension"` // Output file extension including the dot.
+	Args        []string `json:"args"`      // ffmpeg output arguments placed between the input and output.
+}
+
+// Progress reports the state of a running transcode, parsed from ffmpeg's -progress output.
+type Progress struct {
+	OutTime   time.Duration // Position of the encoder in the output.
+	Duration  time.Duration // Duration of the input, zero if unknown.
+	TotalSize int64         // Bytes written so far.
+	Speed     string        // Encoding speed reported by ffmpeg, e.g. "2.5x".
+	Done      bool          // True once ffmpeg reports progress=end.
+}
+
+var (
+	command      = "ffmpeg"  // Command to execute ffmpeg.
+	probeCommand = "ffprobe" // Command to execute ffprobe.
+)
+
+// Presets lists the available transcoding presets.
+var Presets = []Preset{
+	{
+		Name:        "h264_mp4",
+		Description: "H.264 / AAC in MP4, plays almost everywhere",
+		Extension:   ".mp4",
+		Args:        []string{"-c:v", "libx264", "-preset", "medium", "-crf", "23", "-c:a", "aac", "-b:a", "192k", "-movflags", "+faststart"},
+	},
+	{
+		Name:        "hevc",
+		Description: "H.265 / AAC in MP4, smaller files for newer devices",
+		Extension:   ".mp4",
+		Args:        []string{"-c:v", "libx265", "-preset", "medium", "-crf", "28", "-tag:v", "hvc1", "-c:a", "aac", "-b:a", "160k", "-movflags", "+faststart"},
+	},
+	{
+		Name:        "mp3_loudnorm",
+		Description: "Audio only MP3 with EBU R128 loudness normalization",
+		Extension:   ".mp3",
+		Args:        []string{"-vn", "-af", "loudnorm=I=-16:TP=-1.5:LRA=11", "-c:a", "libmp3lame", "-q:a", "2"},
+	},
+	{
+		Name:        "720p_small",
+		Description: "H.264 capped at 720p with a low bitrate for small files",
+		Extension:   ".mp4",
+		Args:        []string{"-vf", "scale=-2:'min(720,ih)'", "-c:v", "libx264", "-preset", "veryfast", "-crf", "28", "-c:a", "aac", "-b:a", "96k", "-movflags", "+faststart"},
+	},
+}
+
+// GetPreset returns the preset with the given name.
+func GetPreset(name string) (Preset, bool) {
+	for _, preset := range Presets {
+		if preset.Name == name {
+			return preset, true
+		}
+	}
+	return Preset{}, false
+}
+
+// CheckIfFfmpegInstalled checks if ffmpeg is installed and available.
+func CheckIfFfmpegInstalled() bool {
+	// Check if ffmpeg is in the system's PATH.
+	if err := exec.Command(command, "-version").Run(); err == nil {
+		return true
+	}
+
+	// Attempt to find ffmpeg executable in the current directory (Windows).
+	if runtime.GOOS == "windows" {
+		if exePath, err := exec.LookPath("./ffmpeg.exe"); err == nil {
+			command = exePath
+			if probePath, err := exec.LookPath("./ffprobe.exe"); err == nil {
+				probeCommand = probePath
+			}
+			return true
+		}
+	}
+
+	// Attempt to find ffmpeg binary in the current directory (Unix).
+	if exePath, err := exec.LookPath("./ffmpeg"); err == nil {
+		command = exePath
+		if probePath, err := exec.LookPath("./ffprobe"); err == nil {
+			probeCommand = probePath
+		}
+		return true
+	}
+
+	return false
+}
+
+// OutputPath returns the default output path for transcoding inputPath with preset,
+// e.g. "video.webm" becomes "video.h264_mp4.mp4".
+func OutputPath(inputPath string, preset Preset) string {
+	base := strings.TrimSuffix(inputPath, filepath.Ext(inputPath))
+	return base + "." + preset.Name + preset.Extension
+}
+
+// Transcode transcodes inputPath with preset next to the input file and returns the output path.
+func Transcode(inputPath string, preset Preset, onProgress func(Progress)) (string, error) {
+	outputPath := OutputPath(inputPath, preset)
+	if err := TranscodeTo(inputPath, outputPath, preset, onProgress); err != nil {
+		return "", err
+	}
+	return outputPath, nil
+}
+
+// TranscodeTo transcodes inputPath into outputPath using preset. If onProgress is
+// not nil it is called every time ffmpeg reports progress.
+func TranscodeTo(inputPath, outputPath string, preset Preset, onProgress func(Progress)) error {
+	if !CheckIfFfmpegInstalled() {
+		return errors.New("ffmpeg is not installed")
+	}
+
+	// The duration is only used for percentages, so a failed probe is not fatal.
+	duration, _ := ProbeDuration(inputPath)
+
+	args := []string{"-hide_banner", "-nostdin", "-y", "-i", inputPath}
+	args = append(args, preset.Args...)
+	args = append(args, "-progress", "pipe:1", "-nostats", outputPath)
+
+	cmd := exec.Command(command, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	readProgress(stdout, duration, onProgress)
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, lastLine(stderr.String()))
+	}
+
+	return nil
+}
+
+// Merge copies the streams of every input into outputPath without re-encoding them, for
+// example a video and an audio stream downloaded separately.
+func Merge(inputPaths []string, outputPath string) error {
+	if !CheckIfFfmpegInstalled() {
+		return errors.New("ffmpeg is not installed")
+	}
+
+	args := []string{"-hide_banner", "-nostdin", "-y"}
+	for _, path := range inputPaths {
+		args = append(args, "-i", path)
+	}
+	for i := range inputPaths {
+		args = append(args, "-map", strconv.Itoa(i))
+	}
+	args = append(args, "-c", "copy", outputPath)
+
+	cmd := exec.Command(command, args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, lastLine(stderr.String()))
+	}
+	return nil
+}
+
+// ProbeDuration returns the duration of a media file using ffprobe.
+func ProbeDuration(path string) (time.Duration, error) {
+	out, err := exec.Command(probeCommand, "-v", "error", "-show_entries", "format=duration", "-of", "default=noprint_wrappers=1:nokey=1", path).Output()
+	if err != nil {
+		return 0, err
+	}
+
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(string(out)), 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// Percent returns the completed percentage, or 0 if the duration is unknown.
+func (p Progress) Percent() float64 {
+	if p.Done {
+		return 100
+	}
+	if p.Duration <= 0 {
+		return 0
+	}
+	return min(100, float64(p.OutTime)/float64(p.Duration)*100)
+}
+
+// readProgress parses ffmpeg's key=value progress blocks from r.
+func readProgress(r io.Reader, duration time.Duration, onProgress func(Progress)) {
+	progress := Progress{Duration: duration}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if parseProgressLine(&progress, scanner.Text()) && onProgress != nil {
+			onProgress(progress)
+		}
+	}
+}
+
+// parseProgressLine applies a single progress line to p. It returns true when the
+// line terminates a progress block.
+func parseProgressLine(p *Progress, line string) bool {
+	key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+	if !ok {
+		return false
+	}
+
+	switch key {
+	case "out_time_us", "out_time_ms":
+		// Despite its name, out_time_ms is also reported in microseconds.
+		if us, err := strconv.ParseInt(value, 10, 64); err == nil {
+			p.OutTime = time.Duration(us) * time.Microsecond
+		}
+	case "total_size":
+		if size, err := strconv.ParseInt(value, 10, 64); err == nil {
+			p.TotalSize = size
+		}
+	case "speed":
+		p.Speed = strings.TrimSpace(value)
+	case "progress":
+		p.Done = value == "end"
+		return true
+	}
+
+	return false
+}
+
+// lastLine returns the last non-empty line of s.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return lines[len(lines)-1]
+}
@@ -0,0 +1,49 @@
+package transcode
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadProgress(t *testing.T) {
+	output := strings.Join([]string{
+		"frame=120",
+		"out_time_us=5000000",
+		"total_size=1048576",
+		"speed=2.5x",
+		"progress=continue",
+		"out_time_us=10000000",
+		"total_size=2097152",
+		"progress=end",
+	}, "\n")
+
+	var reports []Progress
+	readProgress(strings.NewReader(output), 20*time.Second, func(p Progress) {
+		reports = append(reports, p)
+	})
+
+	if len(reports) != 2 {
+		t.Fatalf("Expected 2 progress reports, got %d", len(reports))
+	}
+
+	assert.Equal(t, 5*time.Second, reports[0].OutTime)
+	assert.Equal(t, int64(1048576), reports[0].TotalSize)
+	assert.Equal(t, "2.5x", reports[0].Speed)
+	assert.Equal(t, 25.0, reports[0].Percent())
+	assert.False(t, reports[0].Done)
+
+	assert.True(t, reports[1].Done)
+	assert.Equal(t, 100.0, reports[1].Percent())
+}
+
+func TestOutputPath(t *testing.T) {
+	preset, ok := GetPreset("mp3_loudnorm")
+	if !ok {
+		t.Fatal("Failed to get preset")
+	}
+
+	assert.Equal(t, "downloads/video.mp3_loudnorm.mp3", OutputPath("downloads/video.webm", preset))
+}
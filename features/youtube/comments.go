@@ -0,0 +1,204 @@
+package youtube
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Comment is a single comment of a video, with its replies when part of a tree.
+type Comment struct {
+	ID               string     `json:"id"`
+	Parent           string     `json:"parent"` // "root" for top level comments, otherwise the parent comment ID.
+	Text             string     `json:"text"`
+	Author           string     `json:"author"`
+	AuthorID         string     `json:"author_id,omitempty"`
+	LikeCount        int        `json:"like_count"`
+	Timestamp        int64      `json:"timestamp"` // Unix seconds, approximate for older comments.
+	IsPinned         bool       `json:"is_pinned,omitempty"`
+	AuthorIsUploader bool       `json:"author_is_uploader,omitempty"`
+	Replies          []*Comment `json:"replies,omitempty"`
+}
+
+// CommentOptions limits how many comments are fetched, zero values mean no limit.
+type CommentOptions struct {
+	MaxComments         int // Maximum number of comments including replies.
+	MaxParents          int // Maximum number of top level comments.
+	MaxRepliesPerThread int // Maximum number of replies per top level comment.
+}
+
+// CommentSort selects the order comments are sorted in.
+type CommentSort int
+
+const (
+	SortCommentsByLikes CommentSort = iota
+	SortCommentsByNewest
+	SortCommentsByOldest
+)
+
+// String returns the display name of the comment sort order.
+func (s CommentSort) String() string {
+	switch s {
+	case SortCommentsByNewest:
+		return "newest"
+	case SortCommentsByOldest:
+		return "oldest"
+	default:
+		return "likes"
+	}
+}
+
+// rootCommentParent is the parent value yt-dlp uses for top level comments.
+const rootCommentParent = "root"
+
+// GetComments fetches the comments of a video and returns them as a tree of top level comments.
+func GetComments(url string, opts CommentOptions) ([]*Comment, error) {
+	if !CheckIfYtdlpInstalled() {
+		return nil, errors.New("yt-dlp is not installed")
+	}
+
+	cmd := exec.Command(command, commentArgs(url, opts)...)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var info struct {
+		Comments []Comment `json:"comments"`
+	}
+	if err := json.Unmarshal(out, &info); err != nil {
+		return nil, err
+	}
+
+	return BuildCommentTree(info.Comments), nil
+}
+
+// commentArgs returns the yt-dlp arguments used to fetch comments.
+func commentArgs(url string, opts CommentOptions) []string {
+	args := []string{"-j", "--no-playlist", "--skip-download", "--write-comments"}
+
+	if opts.MaxComments > 0 || opts.MaxParents > 0 || opts.MaxRepliesPerThread > 0 {
+		limit := func(n int) string {
+			if n <= 0 {
+				return "all"
+			}
+			return strconv.Itoa(n)
+		}
+		// max-comments,max-parents,max-replies,max-replies-per-thread
+		args = append(args, "--extractor-args", fmt.Sprintf("youtube:max_comments=%s,%s,all,%s",
+			limit(opts.MaxComments), limit(opts.MaxParents), limit(opts.MaxRepliesPerThread)))
+	}
+
+	return append(args, url)
+}
+
+// BuildCommentTree links a flat list of comments into a tree, keeping their order.
+// Replies whose parent is missing are treated as top level comments.
+func BuildCommentTree(flat []Comment) []*Comment {
+	byID := make(map[string]*Comment, len(flat))
+	comments := make([]*Comment, len(flat))
+	for i := range flat {
+		comments[i] = &flat[i]
+		comments[i].Replies = nil
+		byID[flat[i].ID] = comments[i]
+	}
+
+	var roots []*Comment
+	for _, comment := range comments {
+		parent, ok := byID[comment.Parent]
+		if comment.Parent == rootCommentParent || comment.Parent == "" || !ok {
+			roots = append(roots, comment)
+			continue
+		}
+		parent.Replies = append(parent.Replies, comment)
+	}
+
+	return roots
+}
+
+// SortComments sorts comments and their replies in place. Pinned top level comments stay first.
+func SortComments(comments []*Comment, by CommentSort) {
+	sort.SliceStable(comments, func(i, j int) bool {
+		a, b := comments[i], comments[j]
+		if a.IsPinned != b.IsPinned {
+			return a.IsPinned
+		}
+		switch by {
+		case SortCommentsByNewest:
+			return a.Timestamp > b.Timestamp
+		case SortCommentsByOldest:
+			return a.Timestamp < b.Timestamp
+		default:
+			return a.LikeCount > b.LikeCount
+		}
+	})
+
+	for _, comment := range comments {
+		SortComments(comment.Replies, by)
+	}
+}
+
+// CountComments returns the number of comments in the tree, including replies.
+func CountComments(comments []*Comment) int {
+	count := len(comments)
+	for _, comment := range comments {
+		count += CountComments(comment.Replies)
+	}
+	return count
+}
+
+// Time returns the time the comment was posted.
+func (c *Comment) Time() time.Time {
+	return time.Unix(c.Timestamp, 0)
+}
+
+// ExportCommentsJSON writes the comment tree as indented JSON.
+func ExportCommentsJSON(w io.Writer, comments []*Comment) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(comments)
+}
+
+// ExportCommentsCSV writes the comment tree as a flat CSV table, replies following their parent.
+func ExportCommentsCSV(w io.Writer, comments []*Comment) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"id", "parent", "depth", "author", "likes", "time", "pinned", "text"}); err != nil {
+		return err
+	}
+
+	var write func(comments []*Comment, depth int) error
+	write = func(comments []*Comment, depth int) error {
+		for _, comment := range comments {
+			record := []string{
+				comment.ID,
+				comment.Parent,
+				strconv.Itoa(depth),
+				comment.Author,
+				strconv.Itoa(comment.LikeCount),
+				comment.Time().UTC().Format(time.RFC3339),
+				strconv.FormatBool(comment.IsPinned),
+				comment.Text,
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+			if err := write(comment.Replies, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := write(comments, 0); err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,55 @@
+package youtube
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testComments() []*Comment {
+	return BuildCommentTree([]Comment{
+		{ID: "a", Parent: "root", Text: "First", Author: "alice", LikeCount: 5, Timestamp: 100},
+		{ID: "b", Parent: "root", Text: "Second", Author: "bob", LikeCount: 10, Timestamp: 200},
+		{ID: "a.1", Parent: "a", Text: "Reply, with comma", Author: "carol", LikeCount: 1, Timestamp: 300},
+		{ID: "a.2", Parent: "a", Text: "Another reply", Author: "dave", LikeCount: 3, Timestamp: 400},
+		{ID: "c", Parent: "root", Text: "Pinned", Author: "uploader", IsPinned: true, Timestamp: 50},
+	})
+}
+
+func TestBuildCommentTree(t *testing.T) {
+	roots := testComments()
+
+	assert.Len(t, roots, 3)
+	assert.Len(t, roots[0].Replies, 2)
+	assert.Equal(t, 5, CountComments(roots))
+}
+
+func TestSortComments(t *testing.T) {
+	roots := testComments()
+
+	SortComments(roots, SortCommentsByLikes)
+	assert.Equal(t, []string{"c", "b", "a"}, []string{roots[0].ID, roots[1].ID, roots[2].ID})
+	assert.Equal(t, "a.2", roots[2].Replies[0].ID)
+
+	SortComments(roots, SortCommentsByOldest)
+	assert.Equal(t, []string{"c", "a", "b"}, []string{roots[0].ID, roots[1].ID, roots[2].ID})
+	assert.Equal(t, "a.1", roots[1].Replies[0].ID)
+}
+
+func TestExportCommentsCSV(t *testing.T) {
+	var b strings.Builder
+	if err := ExportCommentsCSV(&b, testComments()); err != nil {
+		t.Fatalf("Failed to export comments: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Len(t, lines, 6)
+	assert.Equal(t, "id,parent,depth,author,likes,time,pinned,text", lines[0])
+	assert.Equal(t, `a.1,a,1,carol,1,1970-01-01T00:05:00Z,false,"Reply, with comma"`, lines[2])
+}
+
+func TestCommentArgs(t *testing.T) {
+	args := commentArgs("https://youtu.be/x", CommentOptions{MaxComments: 100, MaxRepliesPerThread: 5})
+	assert.Contains(t, args, "youtube:max_comments=100,all,all,5")
+}
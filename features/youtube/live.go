@@ -0,0 +1,290 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sterben/features/transcode"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Live status values reported by yt-dlp.
+const (
+	LiveStatusNotLive    = "not_live"
+	LiveStatusIsLive     = "is_live"
+	LiveStatusIsUpcoming = "is_upcoming"
+	LiveStatusWasLive    = "was_live"
+	LiveStatusPostLive   = "post_live"
+)
+
+// LiveOptions configures how a live stream is recorded.
+type LiveOptions struct {
+	WaitForStart bool          // Wait for scheduled streams to start instead of failing.
+	WaitRetry    time.Duration // How often to check whether a scheduled stream started.
+	FromStart    bool          // Record from the start of the stream instead of from now.
+	MaxDuration  time.Duration // Stop recording after this duration, zero records until the stream ends.
+}
+
+// RecordingProgress reports the state of a running live recording.
+type RecordingProgress struct {
+	Waiting bool          // True while waiting for a scheduled stream to start.
+	Elapsed time.Duration // Time spent recording so far.
+	Size    int64         // Bytes written so far, including partial files.
+}
+
+// stopGracePeriod is how long yt-dlp may take to finalize a recording after being interrupted.
+var stopGracePeriod = 15 * time.Second
+
+// recordingPollInterval is how often the recording size is reported.
+var recordingPollInterval = time.Second
+
+// IsLiveStream reports whether the video is currently live or scheduled to go live.
+func (m *VideoMetaData) IsLiveStream() bool {
+	return m.IsLive || m.LiveStatus == LiveStatusIsLive || m.IsUpcoming()
+}
+
+// IsUpcoming reports whether the video is a scheduled stream that has not started yet.
+func (m *VideoMetaData) IsUpcoming() bool {
+	return m.LiveStatus == LiveStatusIsUpcoming
+}
+
+// ScheduledStart returns the scheduled start of an upcoming stream, or the zero time if unknown.
+func (m *VideoMetaData) ScheduledStart() time.Time {
+	if m.ReleaseTimestamp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(m.ReleaseTimestamp, 0)
+}
+
+// RecordLiveStream records a live or upcoming stream into outputDir and returns the
+// path of the recording. If onProgress is not nil it is called about once per second.
+// yt-dlp writes into a temporary directory of its own, so earlier recordings and sidecars of
+// the same video are never mistaken for this one.
+func RecordLiveStream(url, outputDir string, meta *VideoMetaData, opts LiveOptions, onProgress func(RecordingProgress)) (string, error) {
+	if !CheckIfYtdlpInstalled() {
+		return "", errors.New("yt-dlp is not installed")
+	}
+	if meta == nil || meta.ID == "" {
+		return "", errors.New("metadata is required to record a live stream")
+	}
+	if meta.IsUpcoming() && !opts.WaitForStart {
+		return "", errors.New("stream has not started yet")
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", err
+	}
+	recordingDir, err := os.MkdirTemp(outputDir, ".recording-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(recordingDir)
+
+	cmd := exec.Command(command, liveArgs(url, recordingDir, opts)...)
+	out := &strings.Builder{}
+	cmd.Stdout = out
+
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var (
+		stopped   bool
+		started   time.Time
+		ticker    = time.NewTicker(recordingPollInterval)
+		maxTimer  <-chan time.Time
+		graceKill <-chan time.Time
+	)
+	defer ticker.Stop()
+
+	stop := func() {
+		if stopped {
+			return
+		}
+		stopped = true
+
+		// Interrupting lets yt-dlp finalize the file, Windows does not support it.
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			cmd.Process.Kill()
+			return
+		}
+		graceKill = time.After(stopGracePeriod)
+	}
+
+	for {
+		select {
+		case err := <-done:
+			if err != nil && !stopped {
+				return "", err
+			}
+			return finishRecording(out.String(), recordingDir, outputDir)
+
+		case <-ticker.C:
+			size := recordingSize(recordingDir)
+			if started.IsZero() && size > 0 {
+				started = time.Now()
+				if opts.MaxDuration > 0 {
+					maxTimer = time.After(opts.MaxDuration)
+				}
+			}
+			if onProgress != nil {
+				progress := RecordingProgress{Waiting: started.IsZero(), Size: size}
+				if !started.IsZero() {
+					progress.Elapsed = time.Since(started)
+				}
+				onProgress(progress)
+			}
+
+		case <-maxTimer:
+			stop()
+
+		case <-graceKill:
+			cmd.Process.Kill()
+		}
+	}
+}
+
+// liveArgs returns the yt-dlp arguments used to record a live stream.
+func liveArgs(url, outputDir string, opts LiveOptions) []string {
+	args := []string{
+		"-o", filepath.Join(outputDir, "%(title)s [%(id)s].%(ext)s"),
+		"--no-simulate", "--print", "after_move:filepath",
+		// A single muxed format in MPEG-TS keeps the recording playable if it is interrupted,
+		// separate video and audio left unmerged are merged by finishRecording.
+		"-f", "b/bv*+ba",
+		"--hls-use-mpegts",
+	}
+
+	if opts.WaitForStart {
+		retry := opts.WaitRetry
+		if retry <= 0 {
+			retry = time.Minute
+		}
+		args = append(args, "--wait-for-video", strconv.Itoa(int(retry.Seconds())))
+	}
+
+	if opts.FromStart {
+		args = append(args, "--live-from-start")
+	} else {
+		args = append(args, "--no-live-from-start")
+	}
+
+	return append(args, url)
+}
+
+// finishRecording moves the recording from recordingDir into outputDir and returns its path.
+// The recording is the path printed by yt-dlp, or the parts left when the recording was
+// stopped before yt-dlp could move it into place. Separate video and audio parts are merged,
+// and kept in outputDir with an error listing them if that fails. An existing file is never
+// overwritten.
+func finishRecording(output, recordingDir, outputDir string) (string, error) {
+	var recording string
+	if path := strings.TrimSpace(output); path != "" {
+		lines := strings.Split(path, "\n")
+		recording = strings.TrimSpace(lines[len(lines)-1])
+	} else {
+		parts := recordingParts(recordingDir)
+		switch len(parts) {
+		case 0:
+		case 1:
+			recording = parts[0]
+		default:
+			recording = filepath.Join(recordingDir, mergedRecordingName(parts[0]))
+			if err := transcode.Merge(parts, recording); err != nil {
+				var kept []string
+				for _, part := range parts {
+					final := availablePath(filepath.Join(outputDir, filepath.Base(part)))
+					if os.Rename(part, final) == nil {
+						kept = append(kept, final)
+					}
+				}
+				return "", fmt.Errorf("error merging the recording, its parts are kept unmerged as %s: %w", strings.Join(kept, ", "), err)
+			}
+		}
+	}
+	if recording == "" {
+		return "", errors.New("recording produced no output")
+	}
+
+	// Keep the interrupted recording under its final name.
+	final := availablePath(filepath.Join(outputDir, strings.TrimSuffix(filepath.Base(recording), ".part")))
+	if err := os.Rename(recording, final); err != nil {
+		return "", err
+	}
+	return final, nil
+}
+
+// formatSuffix matches the ".f<format id>" yt-dlp adds to the parts of a format it merges.
+var formatSuffix = regexp.MustCompile(`\.f[^.]+$`)
+
+// mergedRecordingName returns the name of the recording merged from part, "<name> [<id>].mkv",
+// which takes any codecs the parts have.
+func mergedRecordingName(part string) string {
+	name := strings.TrimSuffix(filepath.Base(part), ".part")
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return formatSuffix.ReplaceAllString(name, "") + ".mkv"
+}
+
+// recordingParts returns the media files in recordingDir, leaving out the download state
+// files and empty files yt-dlp leaves behind.
+func recordingParts(recordingDir string) []string {
+	var parts []string
+	for _, path := range recordingFiles(recordingDir) {
+		if strings.HasSuffix(path, ".ytdl") {
+			continue
+		}
+		if fi, err := os.Stat(path); err == nil && fi.Size() > 0 {
+			parts = append(parts, path)
+		}
+	}
+	return parts
+}
+
+// availablePath returns path, or path with " (2)", " (3)"... before the extension if it exists.
+func availablePath(path string) string {
+	ext := filepath.Ext(path)
+	candidate := path
+	for n := 2; ; n++ {
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(path, ext), n, ext)
+	}
+}
+
+// recordingSize returns the total size of the files in recordingDir.
+func recordingSize(recordingDir string) int64 {
+	var size int64
+	for _, path := range recordingFiles(recordingDir) {
+		if fi, err := os.Stat(path); err == nil {
+			size += fi.Size()
+		}
+	}
+	return size
+}
+
+// recordingFiles returns the files in recordingDir.
+func recordingFiles(recordingDir string) []string {
+	entries, err := os.ReadDir(recordingDir)
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files = append(files, filepath.Join(recordingDir, entry.Name()))
+		}
+	}
+	return files
+}
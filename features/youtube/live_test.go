@@ -0,0 +1,93 @@
+package youtube
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsLiveStream(t *testing.T) {
+	assert.True(t, (&VideoMetaData{IsLive: true}).IsLiveStream())
+	assert.True(t, (&VideoMetaData{LiveStatus: LiveStatusIsUpcoming}).IsLiveStream())
+	assert.False(t, (&VideoMetaData{LiveStatus: LiveStatusWasLive}).IsLiveStream())
+}
+
+func TestLiveArgs(t *testing.T) {
+	args := liveArgs("https://youtube.com/live/x", "downloads", LiveOptions{
+		WaitForStart: true,
+		WaitRetry:    30 * time.Second,
+		FromStart:    true,
+	})
+
+	assert.Contains(t, args, "--live-from-start")
+	assert.Contains(t, args, "--wait-for-video")
+	assert.Contains(t, args, "30")
+	assert.Equal(t, "https://youtube.com/live/x", args[len(args)-1])
+}
+
+func TestFinishInterruptedRecording(t *testing.T) {
+	dir := t.TempDir()
+	recordingDir := filepath.Join(dir, ".recording-1")
+	assert.NoError(t, os.Mkdir(recordingDir, 0755))
+	files := map[string]string{
+		filepath.Join(recordingDir, "Stream [abc123].mp4.part"): "recording",
+		filepath.Join(recordingDir, "Stream [abc123].mp4.ytdl"): "{}",
+		// An earlier recording and its sidecar are left alone.
+		filepath.Join(dir, "Stream [abc123].mp4"):       "earlier recording",
+		filepath.Join(dir, "Stream [abc123].info.json"): "{}",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	assert.Equal(t, int64(11), recordingSize(recordingDir))
+
+	path, err := finishRecording("", recordingDir, dir)
+	if err != nil {
+		t.Fatalf("Failed to finish recording: %v", err)
+	}
+	assert.Equal(t, filepath.Join(dir, "Stream [abc123] (2).mp4"), path)
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "recording", string(data))
+
+	earlier, err := os.ReadFile(filepath.Join(dir, "Stream [abc123].mp4"))
+	assert.NoError(t, err)
+	assert.Equal(t, "earlier recording", string(earlier))
+}
+
+func TestFinishRecordingPrintedPath(t *testing.T) {
+	dir := t.TempDir()
+	recordingDir := filepath.Join(dir, ".recording-1")
+	assert.NoError(t, os.Mkdir(recordingDir, 0755))
+	recording := filepath.Join(recordingDir, "Stream [abc123].ts")
+	assert.NoError(t, os.WriteFile(recording, []byte("recording"), 0644))
+
+	path, err := finishRecording("[info] done\n"+recording+"\n", recordingDir, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Stream [abc123].ts"), path)
+	assert.FileExists(t, path)
+}
+
+func TestFinishRecordingUnmergedParts(t *testing.T) {
+	dir := t.TempDir()
+	recordingDir := filepath.Join(dir, ".recording-1")
+	assert.NoError(t, os.Mkdir(recordingDir, 0755))
+	video := filepath.Join(recordingDir, "Stream [abc123].f299.mp4.part")
+	audio := filepath.Join(recordingDir, "Stream [abc123].f140.mp4.part")
+	assert.NoError(t, os.WriteFile(video, []byte("not really video"), 0644))
+	assert.NoError(t, os.WriteFile(audio, []byte("audio"), 0644))
+
+	assert.Equal(t, "Stream [abc123].mkv", mergedRecordingName(video))
+
+	// The parts aren't media ffmpeg can merge, so both are kept instead of only the largest.
+	_, err := finishRecording("", recordingDir, dir)
+	assert.ErrorContains(t, err, "unmerged")
+	assert.FileExists(t, filepath.Join(dir, "Stream [abc123].f299.mp4.part"))
+	assert.FileExists(t, filepath.Join(dir, "Stream [abc123].f140.mp4.part"))
+}
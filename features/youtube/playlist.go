@@ -0,0 +1,146 @@
+package youtube
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sterben/features/playlist"
+	"strings"
+	"time"
+)
+
+// PlaylistMetaData holds metadata information for a YouTube playlist.
+type PlaylistMetaData struct {
+	Title    string          `json:"title"`
+	ID       string          `json:"id"`
+	Channel  string          `json:"channel,omitempty"`
+	Uploader string          `json:"uploader,omitempty"`
+	Entries  []VideoMetaData `json:"entries"`
+}
+
+// IsPlaylistURL reports whether url points to a playlist page rather than a single video.
+func IsPlaylistURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return strings.TrimSuffix(u.Path, "/") == "/playlist" && u.Query().Get("list") != ""
+}
+
+// GetPlaylistMetaData retrieves metadata for the specified YouTube playlist URL,
+// with entries in playlist order.
+func GetPlaylistMetaData(url string) (*PlaylistMetaData, error) {
+	if !CheckIfYtdlpInstalled() {
+		return nil, errors.New("yt-dlp is not installed")
+	}
+
+	// Get the playlist without resolving every video.
+	cmd := exec.Command(command, "-J", "--flat-playlist", url)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata PlaylistMetaData
+	if err = json.Unmarshal(out, &metadata); err != nil {
+		return nil, err
+	}
+
+	return &metadata, nil
+}
+
+// DownloadPlaylist downloads every video of a playlist into "<outputDir>/<playlist title>/",
+// prefixed with their playlist index, and writes M3U8 and XSPF playlists in the original
+// order next to them. The download archive is kept in the same directory, so running it again
+// only fetches new videos while videos downloaded elsewhere are not skipped. It returns the
+// paths of the downloaded files.
+func DownloadPlaylist(url, outputDir string, meta *PlaylistMetaData) ([]string, error) {
+	if !CheckIfYtdlpInstalled() {
+		return nil, errors.New("yt-dlp is not installed")
+	}
+	if meta == nil {
+		return nil, errors.New("metadata is required to download a playlist")
+	}
+
+	dir := filepath.Join(outputDir, sanitizeFileName(meta.Title))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(command,
+		"-o", filepath.Join(dir, "%(playlist_index)03d - %(title)s [%(id)s].%(ext)s"),
+		"--download-archive", filepath.Join(dir, ArchiveFileName),
+		"--yes-playlist", "--no-simulate", "--print", "after_move:filepath",
+		url,
+	)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			paths = append(paths, line)
+		}
+	}
+
+	// Videos skipped by the archive are still part of the playlist files.
+	existing, err := os.ReadDir(dir)
+	if err != nil {
+		return paths, err
+	}
+	files := make([]string, 0, len(existing))
+	for _, entry := range existing {
+		if !entry.IsDir() && !isSidecar(entry.Name()) && !strings.HasSuffix(entry.Name(), ".part") {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	if err := WritePlaylistFiles(dir, meta, files); err != nil {
+		return paths, fmt.Errorf("error writing playlist files: %w", err)
+	}
+
+	return paths, nil
+}
+
+// WritePlaylistFiles writes "<title>.m3u8" and "<title>.xspf" into dir, listing the files
+// in paths in playlist order. Files are matched to entries by the "[<id>].<ext>" yt-dlp
+// ends their name with, so copies like transcodes, "[<id>].<preset>.<ext>", are left out.
+func WritePlaylistFiles(dir string, meta *PlaylistMetaData, paths []string) error {
+	entries := PlaylistEntries(meta, paths)
+	name := filepath.Join(dir, sanitizeFileName(meta.Title))
+
+	if err := playlist.Save(name+".m3u8", meta.Title, entries); err != nil {
+		return err
+	}
+	return playlist.Save(name+".xspf", meta.Title, entries)
+}
+
+// PlaylistEntries returns the playlist entries for the downloaded paths, ordered as in meta.
+func PlaylistEntries(meta *PlaylistMetaData, paths []string) []playlist.Entry {
+	var entries []playlist.Entry
+	for _, video := range meta.Entries {
+		for _, path := range paths {
+			if isDownloadOf(filepath.Base(path), video.ID) {
+				entries = append(entries, playlist.Entry{
+					Path:     path,
+					Title:    video.Title,
+					Duration: time.Duration(video.Duration) * time.Second,
+				})
+				break
+			}
+		}
+	}
+	return entries
+}
+
+// isDownloadOf reports whether name is the file yt-dlp wrote for the video id, ending in
+// "[<id>]" followed by a single extension.
+func isDownloadOf(name, id string) bool {
+	return strings.HasSuffix(strings.TrimSuffix(name, filepath.Ext(name)), "["+id+"]")
+}
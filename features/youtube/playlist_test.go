@@ -0,0 +1,49 @@
+package youtube
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPlaylistURL(t *testing.T) {
+	assert.True(t, IsPlaylistURL("https://www.youtube.com/playlist?list=PL123"))
+	assert.False(t, IsPlaylistURL("https://www.youtube.com/watch?v=Tkb2yVr8kfY&list=PL123"))
+	assert.False(t, IsPlaylistURL("https://www.youtube.com/watch?v=Tkb2yVr8kfY"))
+}
+
+func TestPlaylistEntries(t *testing.T) {
+	meta := &PlaylistMetaData{
+		Title: "Mix",
+		Entries: []VideoMetaData{
+			{ID: "aaa", Title: "First", Duration: 60},
+			{ID: "bbb", Title: "Second", Duration: 30},
+			{ID: "ccc", Title: "Missing"},
+		},
+	}
+
+	// Paths arrive in download order, entries keep the playlist order.
+	entries := PlaylistEntries(meta, []string{
+		"downloads/Mix/002 - Second [bbb].webm",
+		"downloads/Mix/001 - First [aaa].mp4",
+	})
+
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "First", entries[0].Title)
+	assert.Equal(t, time.Minute, entries[0].Duration)
+	assert.Equal(t, "downloads/Mix/002 - Second [bbb].webm", entries[1].Path)
+}
+
+func TestPlaylistEntriesTranscoded(t *testing.T) {
+	meta := &PlaylistMetaData{Entries: []VideoMetaData{{ID: "aaa", Title: "First"}}}
+
+	// The transcoded copy sorts before the download in a directory listing.
+	entries := PlaylistEntries(meta, []string{
+		"downloads/Mix/001 - First [aaa].h264_mp4.mp4",
+		"downloads/Mix/001 - First [aaa].webm",
+	})
+
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "downloads/Mix/001 - First [aaa].webm", entries[0].Path)
+}
@@ -0,0 +1,249 @@
+package youtube
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sterben/features/image_convert"
+	"strings"
+)
+
+// SidecarOptions selects which sidecar files are written next to a download.
+type SidecarOptions struct {
+	InfoJSON bool `json:"infoJson"` // Write <name>.info.json.
+	NFO      bool `json:"nfo"`      // Write a Kodi-style <name>.nfo.
+	Poster   bool `json:"poster"`   // Download the thumbnail as <name>-poster.<ext>.
+}
+
+// nfoEpisode is the Kodi/Jellyfin episodedetails document written to .nfo files.
+type nfoEpisode struct {
+	XMLName   xml.Name    `xml:"episodedetails"`
+	Title     string      `xml:"title"`
+	ShowTitle string      `xml:"showtitle,omitempty"`
+	Plot      string      `xml:"plot,omitempty"`
+	Runtime   int         `xml:"runtime,omitempty"` // Minutes.
+	Aired     string      `xml:"aired,omitempty"`
+	Premiered string      `xml:"premiered,omitempty"`
+	Season    string      `xml:"season,omitempty"`
+	Studio    string      `xml:"studio,omitempty"`
+	Genres    []string    `xml:"genre,omitempty"`
+	Tags      []string    `xml:"tag,omitempty"`
+	Thumb     string      `xml:"thumb,omitempty"`
+	UniqueID  nfoUniqueID `xml:"uniqueid"`
+}
+
+// nfoUniqueID identifies the video in the NFO document.
+type nfoUniqueID struct {
+	Type    string `xml:"type,attr"`
+	Default bool   `xml:"default,attr"`
+	Value   string `xml:",chardata"`
+}
+
+// DownloadYoutubeVideoToLibrary downloads a video into the channel/season
+// layout below libraryDir and writes the requested sidecars next to it.
+// It returns the path of the downloaded media file.
+func DownloadYoutubeVideoToLibrary(url, libraryDir string, meta *VideoMetaData, opts SidecarOptions) (string, error) {
+	if !CheckIfYtdlpInstalled() {
+		return "", errors.New("yt-dlp is not installed")
+	}
+	if meta == nil {
+		return "", errors.New("metadata is required for library downloads")
+	}
+
+	dir := LibraryDir(libraryDir, meta)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	mediaPath, err := downloadToTemplate(url, filepath.Join(dir, "%(title)s [%(id)s].%(ext)s"))
+	if err != nil {
+		return "", err
+	}
+
+	if err := WriteSidecars(mediaPath, meta, opts); err != nil {
+		return mediaPath, err
+	}
+
+	return mediaPath, nil
+}
+
+// LibraryDir returns the per-channel, per-season (upload year) directory for a video,
+// e.g. "<root>/<Channel>/Season 2024".
+func LibraryDir(root string, meta *VideoMetaData) string {
+	channel := meta.Channel
+	if channel == "" {
+		channel = meta.Uploader
+	}
+	if channel == "" {
+		channel = "Unknown Channel"
+	}
+
+	season := "Season Unknown"
+	if year := meta.UploadYear(); year != "" {
+		season = "Season " + year
+	}
+
+	return filepath.Join(root, sanitizeFileName(channel), season)
+}
+
+// UploadYear returns the four digit upload year, or an empty string if unknown.
+func (m *VideoMetaData) UploadYear() string {
+	if len(m.UploadDate) < 4 {
+		return ""
+	}
+	return m.UploadDate[:4]
+}
+
+// AiredDate returns the upload date formatted as YYYY-MM-DD, or an empty string if unknown.
+func (m *VideoMetaData) AiredDate() string {
+	if len(m.UploadDate) != 8 {
+		return ""
+	}
+	return fmt.Sprintf("%s-%s-%s", m.UploadDate[:4], m.UploadDate[4:6], m.UploadDate[6:])
+}
+
+// WriteSidecars writes every sidecar enabled in opts next to mediaPath.
+func WriteSidecars(mediaPath string, meta *VideoMetaData, opts SidecarOptions) error {
+	if meta == nil {
+		return errors.New("metadata is required to write sidecars")
+	}
+
+	base := strings.TrimSuffix(mediaPath, filepath.Ext(mediaPath))
+
+	if opts.InfoJSON {
+		if err := WriteInfoJSON(base+".info.json", meta); err != nil {
+			return fmt.Errorf("error writing info.json: %w", err)
+		}
+	}
+
+	if opts.NFO {
+		if err := WriteNFO(base+".nfo", meta); err != nil {
+			return fmt.Errorf("error writing nfo: %w", err)
+		}
+	}
+
+	if opts.Poster && meta.Thumbnail != "" {
+		if err := WritePoster(base+"-poster"+thumbnailExt(meta.Thumbnail), meta); err != nil {
+			return fmt.Errorf("error writing poster: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// WriteInfoJSON writes the metadata as a yt-dlp compatible .info.json file.
+func WriteInfoJSON(path string, meta *VideoMetaData) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// WriteNFO writes the metadata as a Kodi-style episodedetails .nfo file.
+func WriteNFO(path string, meta *VideoMetaData) error {
+	data, err := MarshalNFO(meta)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// MarshalNFO renders the metadata as a Kodi-style episodedetails document.
+func MarshalNFO(meta *VideoMetaData) ([]byte, error) {
+	channel := meta.Channel
+	if channel == "" {
+		channel = meta.Uploader
+	}
+
+	doc := nfoEpisode{
+		Title:     meta.Title,
+		ShowTitle: channel,
+		Plot:      meta.Description,
+		Runtime:   (meta.Duration + 59) / 60,
+		Aired:     meta.AiredDate(),
+		Premiered: meta.AiredDate(),
+		Season:    meta.UploadYear(),
+		Studio:    channel,
+		Genres:    meta.Categories,
+		Tags:      meta.Tags,
+		Thumb:     meta.Thumbnail,
+		UniqueID: nfoUniqueID{
+			Type:    "youtube",
+			Default: true,
+			Value:   meta.ID,
+		},
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// WritePoster downloads the video thumbnail to path.
+func WritePoster(path string, meta *VideoMetaData) error {
+	if meta.Thumbnail == "" {
+		return errors.New("video has no thumbnail")
+	}
+	return downloadFile(meta.Thumbnail, path)
+}
+
+// FetchThumbnail downloads and decodes the video thumbnail, which is often a webp.
+func FetchThumbnail(meta *VideoMetaData) (image.Image, error) {
+	if meta.Thumbnail == "" {
+		return nil, errors.New("video has no thumbnail")
+	}
+	data, err := exec.Command("curl", "-sfL", meta.Thumbnail).Output()
+	if err != nil {
+		return nil, fmt.Errorf("error downloading thumbnail: %w", err)
+	}
+	img, _, err := image_convert.DecodeImage(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding thumbnail: %w", err)
+	}
+	return img, nil
+}
+
+// thumbnailExt returns the file extension of a thumbnail URL, defaulting to .jpg.
+func thumbnailExt(thumbnailURL string) string {
+	u, err := url.Parse(thumbnailURL)
+	if err != nil {
+		return ".jpg"
+	}
+	if ext := path.Ext(u.Path); ext != "" {
+		return ext
+	}
+	return ".jpg"
+}
+
+// posterExtensions are the image extensions a poster written by WriteSidecars can have.
+var posterExtensions = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".webp": true}
+
+// isSidecar reports whether name is a sidecar written by WriteSidecars.
+func isSidecar(name string) bool {
+	if strings.HasSuffix(name, ".info.json") || strings.HasSuffix(name, ".nfo") {
+		return true
+	}
+	ext := filepath.Ext(name)
+	return posterExtensions[strings.ToLower(ext)] && strings.HasSuffix(strings.TrimSuffix(name, ext), "-poster")
+}
+
+// sanitizeFileName replaces characters that are not allowed in file names on common platforms.
+func sanitizeFileName(name string) string {
+	replacer := strings.NewReplacer(
+		"/", "_", "\\", "_", ":", "_", "*", "_", "?", "_",
+		"\"", "_", "<", "_", ">", "_", "|", "_",
+	)
+	return strings.TrimSpace(replacer.Replace(name))
+}
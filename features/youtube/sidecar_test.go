@@ -0,0 +1,61 @@
+package youtube
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testMetaData = &VideoMetaData{
+	Title:       "Test Video",
+	ID:          "Tkb2yVr8kfY",
+	Description: "A description",
+	Duration:    125,
+	Channel:     "Some/Channel",
+	UploadDate:  "20240317",
+	Thumbnail:   "https://i.ytimg.com/vi/Tkb2yVr8kfY/maxresdefault.webp",
+	Tags:        []string{"a", "b"},
+}
+
+func TestLibraryDir(t *testing.T) {
+	dir := LibraryDir("downloads", testMetaData)
+	assert.Equal(t, filepath.Join("downloads", "Some_Channel", "Season 2024"), dir)
+
+	dir = LibraryDir("downloads", &VideoMetaData{Uploader: "Uploader"})
+	assert.Equal(t, filepath.Join("downloads", "Uploader", "Season Unknown"), dir)
+}
+
+func TestMarshalNFO(t *testing.T) {
+	data, err := MarshalNFO(testMetaData)
+	if err != nil {
+		t.Fatalf("Failed to marshal nfo: %v", err)
+	}
+
+	nfo := string(data)
+	assert.True(t, strings.HasPrefix(nfo, "<?xml"))
+	assert.Contains(t, nfo, "<title>Test Video</title>")
+	assert.Contains(t, nfo, "<runtime>3</runtime>")
+	assert.Contains(t, nfo, "<aired>2024-03-17</aired>")
+	assert.Contains(t, nfo, "<season>2024</season>")
+	assert.Contains(t, nfo, `<uniqueid type="youtube" default="true">Tkb2yVr8kfY</uniqueid>`)
+}
+
+func TestThumbnailExt(t *testing.T) {
+	assert.Equal(t, ".webp", thumbnailExt(testMetaData.Thumbnail))
+	assert.Equal(t, ".jpg", thumbnailExt("https://example.com/thumb"))
+}
+
+func TestIsSidecar(t *testing.T) {
+	assert.True(t, isSidecar("001 - Movie [aaa].info.json"))
+	assert.True(t, isSidecar("001 - Movie [aaa].nfo"))
+	assert.True(t, isSidecar("001 - Movie [aaa]-poster.webp"))
+	assert.False(t, isSidecar("001 - Movie-poster.final [aaa].mp4"))
+	assert.False(t, isSidecar("001 - Movie [aaa].mp4"))
+}
+
+func TestFetchThumbnailMissing(t *testing.T) {
+	_, err := FetchThumbnail(&VideoMetaData{Title: "No Thumbnail"})
+	assert.Error(t, err)
+}
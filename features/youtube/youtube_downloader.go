@@ -3,20 +3,39 @@ package youtube
 import (
 	"encoding/json"
 	"errors"
+	"net/url"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 )
 
 // VideoMetaData holds metadata information for a YouTube video.
 type VideoMetaData struct {
-	Title       string `json:"title"`
-	ID          string `json:"id"`
-	Description string `json:"description"`
-	Duration    int    `json:"duration"`
-	ViewCount   int    `json:"view_count"`
+	Title       string   `json:"title"`
+	ID          string   `json:"id"`
+	Description string   `json:"description"`
+	Duration    int      `json:"duration"`
+	ViewCount   int      `json:"view_count"`
+	LikeCount   int      `json:"like_count,omitempty"`
+	Channel     string   `json:"channel,omitempty"`
+	ChannelID   string   `json:"channel_id,omitempty"`
+	Uploader    string   `json:"uploader,omitempty"`
+	UploadDate  string   `json:"upload_date,omitempty"` // Formatted as YYYYMMDD.
+	Thumbnail   string   `json:"thumbnail,omitempty"`
+	WebpageURL  string   `json:"webpage_url,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Categories  []string `json:"categories,omitempty"`
+
+	// Live stream information.
+	IsLive           bool   `json:"is_live,omitempty"`
+	LiveStatus       string `json:"live_status,omitempty"`       // One of not_live, is_live, is_upcoming, was_live or post_live.
+	ReleaseTimestamp int64  `json:"release_timestamp,omitempty"` // Scheduled start of an upcoming stream (unix seconds).
 }
 
+// ArchiveFileName is the name of the yt-dlp download archive used by playlist downloads.
+const ArchiveFileName = "archive.txt"
+
 var (
 	command            = "yt-dlp" // Command to execute yt-dlp.
 	downloadWindowsExe = "https://github.com/yt-dlp/yt-dlp/releases/latest/download/yt-dlp.exe"
@@ -25,18 +44,32 @@ var (
 
 // DownloadYoutubeVideo downloads a YouTube video to the specified output directory.
 func DownloadYoutubeVideo(url, outputDir string) error {
+	_, err := DownloadYoutubeVideoToFile(url, outputDir)
+	return err
+}
+
+// DownloadYoutubeVideoToFile downloads a YouTube video to the specified output directory
+// and returns the path of the downloaded file.
+func DownloadYoutubeVideoToFile(url, outputDir string) (string, error) {
 	if !CheckIfYtdlpInstalled() {
-		return errors.New("yt-dlp is not installed")
+		return "", errors.New("yt-dlp is not installed")
 	}
 
-	// Build the command with output directory and URL.
-	cmd := exec.Command(command, "-o", filepath.Join(outputDir, "%(title)s.%(ext)s"), url)
-	err := cmd.Run()
+	return downloadToTemplate(url, filepath.Join(outputDir, "%(title)s.%(ext)s"))
+}
+
+// downloadToTemplate downloads a video using the given yt-dlp output template
+// and returns the path of the final media file.
+func downloadToTemplate(url, outputTemplate string) (string, error) {
+	// Print the final path once yt-dlp has finished moving the file into place.
+	cmd := exec.Command(command, "-o", outputTemplate, "--no-playlist", "--no-simulate", "--print", "after_move:filepath", url)
+	out, err := cmd.Output()
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
 }
 
 // GetVideoMetaData retrieves metadata for the specified YouTube video URL.
@@ -46,7 +79,7 @@ func GetVideoMetaData(url string) (*VideoMetaData, error) {
 	}
 
 	// Get video metadata in JSON format.
-	cmd := exec.Command(command, "-j", url)
+	cmd := exec.Command(command, "-j", "--no-playlist", url)
 	out, err := cmd.Output()
 	if err != nil {
 		return nil, err
@@ -61,6 +94,29 @@ func GetVideoMetaData(url string) (*VideoMetaData, error) {
 	return &metadata, nil
 }
 
+// supportedHosts lists the hosts of the URLs handled by the youtube feature.
+var supportedHosts = map[string]bool{
+	"youtube.com":       true,
+	"www.youtube.com":   true,
+	"m.youtube.com":     true,
+	"music.youtube.com": true,
+	"youtu.be":          true,
+}
+
+// ExtractURL returns the first supported YouTube URL found in text.
+func ExtractURL(text string) (string, bool) {
+	for _, field := range strings.Fields(text) {
+		u, err := url.Parse(field)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		if supportedHosts[strings.ToLower(u.Hostname())] {
+			return field, true
+		}
+	}
+	return "", false
+}
+
 // CheckIfYtdlpInstalled checks if yt-dlp is installed and available.
 func CheckIfYtdlpInstalled() bool {
 	// Check if yt-dlp is in the system's PATH.
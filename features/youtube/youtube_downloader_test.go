@@ -3,6 +3,8 @@ package youtube
 import (
 	"os"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestDownload(t *testing.T) {
@@ -26,3 +28,15 @@ func TestDownload(t *testing.T) {
 		t.Logf("Title: %s\nID: %s\nDescription: %s\nDuration: %d\nView Count: %d\n", meta.Title, meta.ID, meta.Description, meta.Duration, meta.ViewCount)
 	}
 }
+
+func TestExtractURL(t *testing.T) {
+	url, ok := ExtractURL("watch this https://youtu.be/Tkb2yVr8kfY later")
+	assert.True(t, ok)
+	assert.Equal(t, "https://youtu.be/Tkb2yVr8kfY", url)
+
+	_, ok = ExtractURL("https://example.com/watch?v=Tkb2yVr8kfY")
+	assert.False(t, ok)
+
+	_, ok = ExtractURL("youtube.com without a scheme")
+	assert.False(t, ok)
+}
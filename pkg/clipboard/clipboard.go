@@ -0,0 +1,163 @@
+package clipboard
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/atotto/clipboard"
+)
+
+// ErrUnsupported is returned when the system clipboard is not available.
+var ErrUnsupported = errors.New("clipboard is not supported on this system")
+
+// Clipboard interface defines read access to a clipboard.
+type Clipboard interface {
+	ReadAll() (string, error)
+}
+
+// system implements the Clipboard interface using the system clipboard.
+type system struct{}
+
+// NewSystem returns a Clipboard backed by the system clipboard.
+func NewSystem() Clipboard {
+	return &system{}
+}
+
+// ReadAll reads the text currently on the system clipboard.
+func (s *system) ReadAll() (string, error) {
+	if clipboard.Unsupported {
+		return "", ErrUnsupported
+	}
+	return clipboard.ReadAll()
+}
+
+// Fake implements the Clipboard interface in memory, for tests.
+type Fake struct {
+	mutex sync.Mutex
+	text  string
+	err   error
+}
+
+// NewFake returns an empty in-memory clipboard.
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+// Set replaces the text on the fake clipboard.
+func (f *Fake) Set(text string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.text = text
+}
+
+// SetError makes subsequent reads fail with err, nil clears it.
+func (f *Fake) SetError(err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.err = err
+}
+
+// ReadAll returns the text on the fake clipboard.
+func (f *Fake) ReadAll() (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.text, f.err
+}
+
+// Watcher polls a clipboard and reports each new text accepted by its match function once.
+type Watcher struct {
+	clipboard Clipboard
+	interval  time.Duration
+	match     func(text string) (string, bool)
+
+	mutex sync.Mutex
+	last  string          // Clipboard text seen on the previous check.
+	seen  map[string]bool // Matches that have already been reported.
+	stop  chan struct{}
+}
+
+// NewWatcher creates a watcher that polls c every interval. match extracts the value
+// to report from the clipboard text and returns false if there is nothing to report.
+func NewWatcher(c Clipboard, interval time.Duration, match func(text string) (string, bool)) *Watcher {
+	return &Watcher{
+		clipboard: c,
+		interval:  interval,
+		match:     match,
+		seen:      make(map[string]bool),
+	}
+}
+
+// Check reads the clipboard once and returns a match that has not been reported before.
+func (w *Watcher) Check() (string, bool, error) {
+	text, err := w.clipboard.ReadAll()
+	if err != nil {
+		return "", false, err
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if text == w.last {
+		return "", false, nil
+	}
+	w.last = text
+
+	value, ok := w.match(text)
+	if !ok || w.seen[value] {
+		return "", false, nil
+	}
+	w.seen[value] = true
+
+	return value, true, nil
+}
+
+// Start polls the clipboard in a goroutine and calls onMatch for every new match
+// until Stop is called. Read errors are passed to onError if it is not nil.
+func (w *Watcher) Start(onMatch func(value string), onError func(err error)) {
+	w.mutex.Lock()
+	if w.stop != nil {
+		w.mutex.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	w.stop = stop
+	w.mutex.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				value, ok, err := w.Check()
+				if err != nil {
+					if onError != nil {
+						onError(err)
+					}
+					continue
+				}
+				if ok {
+					onMatch(value)
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops polling the clipboard.
+func (w *Watcher) Stop() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.stop != nil {
+		close(w.stop)
+		w.stop = nil
+	}
+}
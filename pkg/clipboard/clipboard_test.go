@@ -0,0 +1,63 @@
+package clipboard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// matchURL accepts any text that starts with https://.
+func matchURL(text string) (string, bool) {
+	text = strings.TrimSpace(text)
+	return text, strings.HasPrefix(text, "https://")
+}
+
+func TestWatcherCheck(t *testing.T) {
+	fake := NewFake()
+	w := NewWatcher(fake, time.Second, matchURL)
+
+	fake.Set("not a url")
+	_, ok, err := w.Check()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	fake.Set("https://youtu.be/a")
+	url, ok, _ := w.Check()
+	assert.True(t, ok)
+	assert.Equal(t, "https://youtu.be/a", url)
+
+	// The same URL is not offered twice, even after the clipboard changed in between.
+	_, ok, _ = w.Check()
+	assert.False(t, ok)
+	fake.Set("something else")
+	w.Check()
+	fake.Set(" https://youtu.be/a ")
+	_, ok, _ = w.Check()
+	assert.False(t, ok)
+
+	fake.SetError(errors.New("read failed"))
+	_, _, err = w.Check()
+	assert.Error(t, err)
+}
+
+func TestWatcherStart(t *testing.T) {
+	fake := NewFake()
+	fake.Set("https://youtu.be/b")
+
+	w := NewWatcher(fake, time.Millisecond, matchURL)
+	matches := make(chan string, 1)
+	w.Start(func(value string) {
+		matches <- value
+	}, nil)
+	defer w.Stop()
+
+	select {
+	case url := <-matches:
+		assert.Equal(t, "https://youtu.be/b", url)
+	case <-time.After(time.Second):
+		t.Fatal("Watcher did not report the clipboard URL")
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
-	"reflect"
 	"strings"
 )
 
@@ -15,7 +14,75 @@ var (
 
 // Config holds the application configuration settings.
 type Config struct {
-	Test string `json:"test"`
+	Test      string          `json:"test"`
+	Youtube   YoutubeConfig   `json:"youtube"`
+	Library   LibraryConfig   `json:"library"`
+	Retention RetentionConfig `json:"retention"`
+	Clipboard ClipboardConfig `json:"clipboard"`
+	Image     ImageConfig     `json:"image"`
+}
+
+// ImageConfig holds the settings used by the image conversion pages.
+type ImageConfig struct {
+	EffectPresets []EffectPresetConfig `json:"effectPresets"` // Named effect pipelines offered on the Image to Icon page.
+}
+
+// EffectPresetConfig is a named, ordered list of icon effects.
+type EffectPresetConfig struct {
+	Name    string         `json:"name"`
+	Effects []EffectConfig `json:"effects"`
+}
+
+// EffectConfig is a step of an effect preset. Lengths are fractions of the shortest image side.
+type EffectConfig struct {
+	Type    string  `json:"type"`              // "round", "circle", "padding", "background", "border" or "shadow".
+	Radius  float64 `json:"radius,omitempty"`  // Corner radius of "round" and "border".
+	Amount  float64 `json:"amount,omitempty"`  // Padding, border width or shadow blur.
+	Color   string  `json:"color,omitempty"`   // "#rrggbb" or "#rrggbbaa" color of "background", "border" and "shadow".
+	OffsetX float64 `json:"offsetX,omitempty"` // Shadow offset.
+	OffsetY float64 `json:"offsetY,omitempty"`
+}
+
+// LibraryConfig holds the settings used by the downloads library.
+type LibraryConfig struct {
+	Player string `json:"player"` // External player command, empty to use the system default.
+}
+
+// ClipboardConfig holds the settings of the clipboard watcher.
+type ClipboardConfig struct {
+	Watch      bool `json:"watch"`      // Offer supported URLs copied to the clipboard.
+	IntervalMs int  `json:"intervalMs"` // How often the clipboard is checked.
+}
+
+// RetentionConfig holds the cleanup policy for the downloads directory.
+type RetentionConfig struct {
+	MaxTotalSizeMB int      `json:"maxTotalSizeMb"` // Maximum size of the downloads directory, 0 for no limit.
+	MaxAgeDays     int      `json:"maxAgeDays"`     // Maximum age of a download, 0 for no limit.
+	Keep           []string `json:"keep"`           // Pinned paths, file name globs or video IDs that are never removed.
+	Strategy       string   `json:"strategy"`       // "oldest" or "lru".
+}
+
+// YoutubeConfig holds the settings used by the youtube feature.
+type YoutubeConfig struct {
+	LibraryLayout bool `json:"libraryLayout"` // Store downloads as <Channel>/Season <Year>/.
+	WriteInfoJSON bool `json:"writeInfoJson"` // Write a .info.json sidecar after each download.
+	WriteNFO      bool `json:"writeNfo"`      // Write a Kodi-style .nfo sidecar after each download.
+	WritePoster   bool `json:"writePoster"`   // Save the thumbnail as a poster image after each download.
+
+	// PostDownloadPreset names a transcode preset run after each download, empty to disable.
+	PostDownloadPreset string `json:"postDownloadPreset"`
+
+	Live LiveConfig `json:"live"`
+
+	MaxComments         int `json:"maxComments"`         // Maximum number of comments fetched per video, 0 for no limit.
+	MaxRepliesPerThread int `json:"maxRepliesPerThread"` // Maximum number of replies fetched per thread, 0 for no limit.
+}
+
+// LiveConfig holds the settings used when recording live streams.
+type LiveConfig struct {
+	WaitForStart       bool `json:"waitForStart"`       // Wait for scheduled streams to start.
+	FromStart          bool `json:"fromStart"`          // Record from the start of the stream instead of from now.
+	MaxDurationMinutes int  `json:"maxDurationMinutes"` // Stop recording after this many minutes, 0 for no limit.
 }
 
 // Global variable to hold the configuration in memory.
@@ -24,6 +91,59 @@ var config *Config
 // Default configuration values.
 var defaultConfig = &Config{
 	Test: "123",
+	Youtube: YoutubeConfig{
+		LibraryLayout: false,
+		WriteInfoJSON: true,
+		WriteNFO:      true,
+		WritePoster:   true,
+
+		PostDownloadPreset: "",
+
+		Live: LiveConfig{
+			WaitForStart:       true,
+			FromStart:          false,
+			MaxDurationMinutes: 0,
+		},
+
+		MaxComments:         500,
+		MaxRepliesPerThread: 0,
+	},
+	Library: LibraryConfig{
+		Player: "",
+	},
+	Retention: RetentionConfig{
+		MaxTotalSizeMB: 0,
+		MaxAgeDays:     0,
+		Keep:           []string{},
+		Strategy:       "oldest",
+	},
+	Clipboard: ClipboardConfig{
+		Watch:      false,
+		IntervalMs: 1000,
+	},
+	Image: ImageConfig{
+		EffectPresets: []EffectPresetConfig{
+			{Name: "rounded", Effects: []EffectConfig{
+				{Type: "round", Radius: 0.2},
+			}},
+			{Name: "circle", Effects: []EffectConfig{
+				{Type: "circle"},
+			}},
+			{Name: "app tile", Effects: []EffectConfig{
+				{Type: "padding", Amount: 0.12},
+				{Type: "background", Color: "#ffffff"},
+				{Type: "round", Radius: 0.22},
+				{Type: "padding", Amount: 0.04},
+				{Type: "shadow", Amount: 0.03, OffsetY: 0.015, Color: "#00000066"},
+			}},
+			{Name: "badge", Effects: []EffectConfig{
+				{Type: "circle"},
+				{Type: "border", Radius: 0.5, Amount: 0.04, Color: "#ffffff"},
+				{Type: "padding", Amount: 0.06},
+				{Type: "shadow", Amount: 0.03, OffsetY: 0.02},
+			}},
+		},
+	},
 }
 
 // Init initializes the configuration by either creating a new config file
@@ -143,21 +263,55 @@ func getDataFromConfigFile() (*Config, error) {
 	return config, nil
 }
 
-// ensureAllKeysExist checks if all keys in the default configuration are
-// present in the provided rawConfig. If any keys are missing, it adds them
-// with their zero values.
+// ensureAllKeysExist adds the keys of the default configuration that are missing from
+// rawConfig, including nested ones, with their default values. It reports whether any
+// key was added.
 func ensureAllKeysExist(rawConfig map[string]interface{}) bool {
+	data, err := json.Marshal(defaultConfig)
+	if err != nil {
+		return false
+	}
+	var defaults map[string]interface{}
+	if err := json.Unmarshal(data, &defaults); err != nil {
+		return false
+	}
+	return addMissingKeys(rawConfig, defaults)
+}
+
+// addMissingKeys copies the keys of defaults missing from m, descending into nested objects.
+// Keys are matched without case, like json.Unmarshal does.
+func addMissingKeys(m, defaults map[string]interface{}) bool {
 	updated := false
-	expectedKeys := getExpectedKeys()
-	for _, key := range expectedKeys {
-		if _, ok := rawConfig[key]; !ok {
-			rawConfig[key] = getZeroValueForKey(key)
+	for key, def := range defaults {
+		value, ok := lookupKey(m, key)
+		if !ok {
+			m[key] = def
+			updated = true
+			continue
+		}
+
+		nested, ok := value.(map[string]interface{})
+		nestedDefaults, isObject := def.(map[string]interface{})
+		if ok && isObject && addMissingKeys(nested, nestedDefaults) {
 			updated = true
 		}
 	}
 	return updated
 }
 
+// lookupKey returns the value of key in m, ignoring case.
+func lookupKey(m map[string]interface{}, key string) (interface{}, bool) {
+	if value, ok := m[key]; ok {
+		return value, true
+	}
+	for k, value := range m {
+		if strings.EqualFold(k, key) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
 // writeUpdatedConfigFile writes the updated rawConfig map to the config file.
 func writeUpdatedConfigFile(rawConfig map[string]interface{}) error {
 	f, err := os.Create(getConfigFilePath())
@@ -175,28 +329,6 @@ func writeUpdatedConfigFile(rawConfig map[string]interface{}) error {
 	return err
 }
 
-// getExpectedKeys returns a list of all expected keys (JSON tags) in the Config struct.
-func getExpectedKeys() []string {
-	var keys []string
-	val := reflect.TypeOf(Config{})
-	for i := 0; i < val.NumField(); i++ {
-		keys = append(keys, strings.ToLower(val.Field(i).Tag.Get("json")))
-	}
-	return keys
-}
-
-// getZeroValueForKey returns the zero value for the given key in the Config struct.
-func getZeroValueForKey(key string) interface{} {
-	val := reflect.TypeOf(Config{})
-	for i := 0; i < val.NumField(); i++ {
-		field := val.Field(i)
-		if strings.ToLower(field.Tag.Get("json")) == key {
-			return reflect.Zero(field.Type).Interface()
-		}
-	}
-	return nil
-}
-
 // mapToStruct converts a map to a Config struct using JSON marshaling.
 func mapToStruct(m map[string]interface{}, s *Config) error {
 	data, err := json.Marshal(m)
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnsureAllKeysExist(t *testing.T) {
+	// A config written before the retention, clipboard and image sections and some youtube keys.
+	rawConfig := map[string]interface{}{
+		"test": "abc",
+		"youtube": map[string]interface{}{
+			"libraryLayout": true,
+			"WriteNFO":      false,
+		},
+		"library": map[string]interface{}{"player": "mpv"},
+	}
+
+	assert.True(t, ensureAllKeysExist(rawConfig))
+
+	cfg := &Config{}
+	assert.NoError(t, mapToStruct(rawConfig, cfg))
+	assert.Equal(t, "abc", cfg.Test)
+	assert.Equal(t, "mpv", cfg.Library.Player)
+	assert.True(t, cfg.Youtube.LibraryLayout, "existing values are kept")
+	assert.False(t, cfg.Youtube.WriteNFO, "keys are matched without case")
+	assert.True(t, cfg.Youtube.WriteInfoJSON)
+	assert.True(t, cfg.Youtube.Live.WaitForStart)
+	assert.Equal(t, 500, cfg.Youtube.MaxComments)
+	assert.Equal(t, "oldest", cfg.Retention.Strategy)
+	assert.Equal(t, 1000, cfg.Clipboard.IntervalMs)
+	assert.Len(t, cfg.Image.EffectPresets, len(defaultConfig.Image.EffectPresets))
+
+	assert.False(t, ensureAllKeysExist(rawConfig), "nothing is missing anymore")
+}
@@ -42,6 +42,17 @@ type Pages struct {
 	Mutex      sync.Mutex
 	Models     map[PageType]tea.Model
 	Navigation []tea.Model
+	Toast      *Toast
+
+	toastID int
+	program *tea.Program
+}
+
+// PageMsg delivers Msg to the model of Page whether or not it is the current model, so
+// results of work started by a page reach it after the user navigated away.
+type PageMsg struct {
+	Page PageType
+	Msg  tea.Msg
 }
 
 // Initialize creates a new Pages instance and stores it as a singleton.
@@ -74,14 +85,30 @@ func (p *Pages) Close() {
 }
 
 // Update handles updates to the current model based on messages received.
+// Pages stays the program's model so toasts are shown on top of every page,
+// the current model is tracked by the navigation stack.
 func (p *Pages) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if cmd, handled := p.handleToast(msg); handled {
+		return p, cmd
+	}
+	if msg, ok := msg.(PageMsg); ok {
+		m, ok := p.GetModel(msg.Page)
+		if !ok {
+			p.Log.Error().Str("page", string(msg.Page.ID)).Msg("Failed to deliver message to page")
+			return p, nil
+		}
+		_, cmd := m.Update(msg.Msg)
+		return p, cmd
+	}
+
 	m, err := p.CurrentModel()
 	if err != nil {
 		p.Log.Error().Err(*err).Msg("Failed to get current model")
-		return nil, tea.Quit
+		return p, tea.Quit
 	}
 
-	return m.Update(msg)
+	_, cmd := m.Update(msg)
+	return p, cmd
 }
 
 // View renders the view of the current model.
@@ -92,7 +119,23 @@ func (p *Pages) View() string {
 		return ""
 	}
 
-	return m.View()
+	return p.renderToast(m.View())
+}
+
+// SetProgram sets the program running the pages, which Send delivers messages to.
+// It must be called before the program is run.
+func (p *Pages) SetProgram(program *tea.Program) {
+	p.program = program
+}
+
+// Send delivers msg to the model of page from a goroutine, see PageMsg.
+// Messages are dropped when no program is set.
+func (p *Pages) Send(page PageType, msg tea.Msg) {
+	if p.program == nil {
+		p.Log.Warn().Str("page", string(page.ID)).Msg("No program to send message to")
+		return
+	}
+	p.program.Send(PageMsg{Page: page, Msg: msg})
 }
 
 // AddModel adds a new model to the Pages instance and logs the action.
@@ -43,3 +43,76 @@ func TestAddModel(t *testing.T) {
 	// Assert that the model was added
 	assert.Equal(t, mock, model)
 }
+
+func TestToast(t *testing.T) {
+	p := Initialize(Config{
+		Log: log.New(log.Config{
+			Feature:       "pages_test",
+			ConsoleOutput: false,
+			FileOutput:    false,
+		}),
+	})
+	p.AddModel(testPage, mockModel{})
+	p.SwitchModel(testPage)
+
+	// Show a toast with an action
+	ran := false
+	p.Update(ShowToastMsg{
+		Message: "Toast",
+		Actions: []ToastAction{{
+			Key:   "ctrl+f",
+			Label: "Run",
+			Run: func() tea.Cmd {
+				ran = true
+				return nil
+			},
+		}},
+	})
+	assert.NotNil(t, p.Toast)
+	assert.Contains(t, p.View(), "Toast")
+
+	// Keys without an action are passed on to the current model
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	assert.NotNil(t, p.Toast)
+
+	// The action dismisses the toast
+	model, _ := p.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	assert.True(t, ran)
+	assert.Nil(t, p.Toast)
+	assert.Equal(t, p, model)
+}
+
+// recordingModel records the messages it receives.
+type recordingModel struct {
+	msgs *[]tea.Msg
+}
+
+func (m recordingModel) Init() tea.Cmd { return nil }
+func (m recordingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	*m.msgs = append(*m.msgs, msg)
+	return m, nil
+}
+func (m recordingModel) View() string { return "recording" }
+
+func TestPageMsg(t *testing.T) {
+	p := Initialize(Config{
+		Log: log.New(log.Config{
+			Feature:       "pages_test",
+			ConsoleOutput: false,
+			FileOutput:    false,
+		}),
+	})
+	background := PageType{ID: "background", Name: "Background"}
+	var msgs []tea.Msg
+	p.AddModel(background, recordingModel{&msgs})
+	p.AddModel(testPage, mockModel{})
+	p.SwitchModel(testPage)
+
+	// Page messages reach their page even when another one is shown
+	p.Update(PageMsg{Page: background, Msg: "done"})
+	assert.Equal(t, []tea.Msg{"done"}, msgs)
+
+	// Without a program, sent messages are dropped
+	p.Send(background, "dropped")
+	assert.Len(t, msgs, 1)
+}
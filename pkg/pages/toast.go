@@ -0,0 +1,118 @@
+package pages
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ToastAction is a key binding offered by a toast.
+type ToastAction struct {
+	Key   string         // Key that triggers the action, e.g. "ctrl+f".
+	Label string         // Short description shown in the toast.
+	Run   func() tea.Cmd // Called when the key is pressed, the toast is dismissed first.
+}
+
+// Toast is a notification shown on top of the current page until it is dismissed,
+// one of its actions is triggered or it times out.
+type Toast struct {
+	Message string
+	Actions []ToastAction
+	Timeout time.Duration // Zero keeps the toast until it is dismissed.
+
+	id int
+}
+
+// ToastDismissKey is the key that dismisses the current toast.
+const ToastDismissKey = "ctrl+x"
+
+// ShowToastMsg is a message that shows a toast, use it with tea.Program.Send from goroutines.
+type ShowToastMsg Toast
+
+// dismissToastMsg dismisses the toast with the given id once its timeout expires.
+type dismissToastMsg struct {
+	id int
+}
+
+// ShowToast shows t, replacing any toast currently shown.
+func (p *Pages) ShowToast(t Toast) tea.Cmd {
+	p.Mutex.Lock()
+	defer p.Mutex.Unlock()
+
+	p.toastID++
+	t.id = p.toastID
+	p.Toast = &t
+
+	if t.Timeout <= 0 {
+		return nil
+	}
+	return tea.Tick(t.Timeout, func(time.Time) tea.Msg {
+		return dismissToastMsg{id: t.id}
+	})
+}
+
+// DismissToast hides the current toast.
+func (p *Pages) DismissToast() {
+	p.Mutex.Lock()
+	defer p.Mutex.Unlock()
+
+	p.Toast = nil
+}
+
+// handleToast handles messages addressed to the toast. It returns false if the
+// message should be passed on to the current model.
+func (p *Pages) handleToast(msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case ShowToastMsg:
+		return p.ShowToast(Toast(msg)), true
+
+	case dismissToastMsg:
+		if p.Toast != nil && p.Toast.id == msg.id {
+			p.DismissToast()
+		}
+		return nil, true
+
+	case tea.KeyMsg:
+		if p.Toast == nil {
+			return nil, false
+		}
+		if msg.String() == ToastDismissKey {
+			p.DismissToast()
+			return nil, true
+		}
+		for _, action := range p.Toast.Actions {
+			if msg.String() == action.Key {
+				p.DismissToast()
+				return action.Run(), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// renderToast draws the toast over the last line of view.
+func (p *Pages) renderToast(view string) string {
+	if p.Toast == nil {
+		return view
+	}
+
+	text := p.Toast.Message
+	for _, action := range p.Toast.Actions {
+		text += "  [" + action.Key + "] " + action.Label
+	}
+	text += "  [" + ToastDismissKey + "] dismiss"
+
+	toast := lipgloss.NewStyle().
+		Bold(true).
+		Padding(0, 1).
+		Foreground(lipgloss.Color("#000000")).
+		Background(lipgloss.Color(ThemeColorPrimary)).
+		Render(text)
+
+	lines := strings.Split(view, "\n")
+	lines[len(lines)-1] = toast
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,420 @@
+package termimage
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/term"
+)
+
+// Mode is the kind of characters an image is drawn with.
+type Mode int
+
+const (
+	HalfBlocks Mode = iota // Upper half blocks, two pixels per cell.
+	ASCII                  // A brightness ramp of ASCII characters, one pixel per cell.
+	Braille                // Braille patterns, a 2x4 grid of dots per cell.
+)
+
+// Modes lists every render mode.
+var Modes = []Mode{HalfBlocks, ASCII, Braille}
+
+// String returns the name of the mode.
+func (m Mode) String() string {
+	switch m {
+	case ASCII:
+		return "ascii"
+	case Braille:
+		return "braille"
+	default:
+		return "halfblocks"
+	}
+}
+
+// Next returns the mode after m in Modes, wrapping around.
+func (m Mode) Next() Mode {
+	return Modes[(int(m)+1)%len(Modes)]
+}
+
+// ParseMode returns the mode with the given name.
+func ParseMode(name string) (Mode, error) {
+	for _, m := range Modes {
+		if strings.EqualFold(name, m.String()) {
+			return m, nil
+		}
+	}
+	return HalfBlocks, fmt.Errorf("unknown render mode %q", name)
+}
+
+// ColorDepth is the kind of color escape codes an image is drawn with.
+type ColorDepth int
+
+const (
+	TrueColor ColorDepth = iota // 24-bit colors.
+	Color256                    // The xterm 256 color palette.
+	NoColor                     // Plain text, shapes only.
+)
+
+// ColorDepths lists every color depth.
+var ColorDepths = []ColorDepth{TrueColor, Color256, NoColor}
+
+// String returns the name of the color depth.
+func (d ColorDepth) String() string {
+	switch d {
+	case Color256:
+		return "256"
+	case NoColor:
+		return "none"
+	default:
+		return "truecolor"
+	}
+}
+
+// Next returns the color depth after d in ColorDepths, wrapping around.
+func (d ColorDepth) Next() ColorDepth {
+	return ColorDepths[(int(d)+1)%len(ColorDepths)]
+}
+
+// DefaultRamp is the ASCII ramp, from the darkest to the brightest pixels.
+const DefaultRamp = " .:-=+*#%@"
+
+// Options configures Render.
+type Options struct {
+	Mode  Mode
+	Color ColorDepth
+	// Width is the number of columns drawn, TerminalWidth if 0. Images are never drawn wider.
+	Width int
+	// MaxHeight is the largest number of rows drawn, 0 for no limit. The width shrinks to keep the aspect ratio.
+	MaxHeight int
+	// Checkerboard draws transparent pixels over a grey checkerboard instead of the terminal background.
+	Checkerboard bool
+	// Ramp is the ASCII characters from the darkest to the brightest, DefaultRamp if empty.
+	Ramp string
+}
+
+// cellAspect is how many times taller than wide a terminal cell is.
+const cellAspect = 2
+
+// TerminalWidth returns the width of the terminal on stdout, or 80 if it isn't a terminal.
+func TerminalWidth() int {
+	w, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || w <= 0 {
+		return 80
+	}
+	return w
+}
+
+// cellPixels returns how many pixels wide and tall a cell is in mode m.
+func (m Mode) cellPixels() (int, int) {
+	switch m {
+	case ASCII:
+		return 1, 1
+	case Braille:
+		return 2, 4
+	default:
+		return 1, 2
+	}
+}
+
+// Size returns the columns and rows an image of the given size takes with opts.
+func Size(size image.Point, opts Options) (int, int) {
+	if size.X <= 0 || size.Y <= 0 {
+		return 0, 0
+	}
+	cols := opts.Width
+	if cols <= 0 {
+		cols = TerminalWidth()
+	}
+
+	// Cells are about twice as tall as wide, so a row covers cellAspect columns of height.
+	rows := func(cols int) int {
+		return max(1, (size.Y*cols+size.X*cellAspect/2)/(size.X*cellAspect))
+	}
+	r := rows(cols)
+	if opts.MaxHeight > 0 && r > opts.MaxHeight {
+		cols = max(1, opts.MaxHeight*size.X*cellAspect/size.Y)
+		r = min(rows(cols), opts.MaxHeight)
+	}
+	return cols, r
+}
+
+// Render draws img as terminal art. Lines end with a newline, and with a reset code when colored.
+func Render(img image.Image, opts Options) string {
+	cols, rows := Size(img.Bounds().Size(), opts)
+	if cols == 0 || rows == 0 {
+		return ""
+	}
+	cw, ch := opts.Mode.cellPixels()
+	pixels := scale(img, cols*cw, rows*ch)
+
+	w := &writer{depth: opts.Color}
+	at := func(x, y int) color.NRGBA {
+		c := pixels.NRGBAAt(x, y)
+		if opts.Checkerboard {
+			c = overChecker(c, x/cw, y/ch)
+		}
+		return c
+	}
+
+	switch opts.Mode {
+	case ASCII:
+		ramp := []rune(opts.Ramp)
+		if len(ramp) == 0 {
+			ramp = []rune(DefaultRamp)
+		}
+		renderASCII(w, at, cols, rows, ramp)
+	case Braille:
+		renderBraille(w, at, cols, rows)
+	default:
+		renderHalfBlocks(w, at, cols, rows)
+	}
+	return w.String()
+}
+
+// scale resizes img to w by h, with nearest neighbor when enlarging to keep pixel art crisp.
+func scale(img image.Image, w, h int) *image.NRGBA {
+	b := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, w, h))
+	var interpolator draw.Interpolator = draw.CatmullRom
+	if w >= b.Dx() && h >= b.Dy() {
+		interpolator = draw.NearestNeighbor
+	}
+	interpolator.Scale(out, out.Bounds(), img, b, draw.Src, nil)
+	return out
+}
+
+// overChecker blends c onto a grey checkerboard of two by two cells.
+func overChecker(c color.NRGBA, col, row int) color.NRGBA {
+	checker := uint8(0x66)
+	if (col/2+row/2)%2 == 0 {
+		checker = 0x99
+	}
+	blend := func(v uint8) uint8 {
+		return uint8((int(v)*int(c.A) + int(checker)*(255-int(c.A))) / 255)
+	}
+	return color.NRGBA{blend(c.R), blend(c.G), blend(c.B), 255}
+}
+
+// luma returns the brightness of c from 0 to 255, ignoring alpha.
+func luma(c color.NRGBA) float64 {
+	return 0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)
+}
+
+// transparent reports whether c shows the terminal background.
+func transparent(c color.NRGBA) bool {
+	return c.A < 128
+}
+
+// renderASCII draws one character per pixel, picked from ramp by brightness.
+func renderASCII(w *writer, at func(x, y int) color.NRGBA, cols, rows int, ramp []rune) {
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			c := at(x, y)
+			if transparent(c) {
+				w.cell(nil, nil, ' ')
+				continue
+			}
+			i := int(luma(c) * float64(len(ramp)) / 256)
+			w.cell(&c, nil, ramp[min(i, len(ramp)-1)])
+		}
+		w.endLine()
+	}
+}
+
+// renderHalfBlocks draws two pixels per cell, the top one as the foreground of ▀ and the
+// bottom one as its background. Without colors, pixels brighter than the middle are drawn.
+func renderHalfBlocks(w *writer, at func(x, y int) color.NRGBA, cols, rows int) {
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			top, bottom := at(x, 2*y), at(x, 2*y+1)
+			if w.depth == NoColor {
+				on := func(c color.NRGBA) bool { return !transparent(c) && luma(c) >= 128 }
+				w.cell(nil, nil, []rune{' ', '▄', '▀', '█'}[btoi(on(top))*2+btoi(on(bottom))])
+				continue
+			}
+
+			switch {
+			case transparent(top) && transparent(bottom):
+				w.cell(nil, nil, ' ')
+			case transparent(top):
+				w.cell(&bottom, nil, '▄')
+			case transparent(bottom):
+				w.cell(&top, nil, '▀')
+			default:
+				w.cell(&top, &bottom, '▀')
+			}
+		}
+		w.endLine()
+	}
+}
+
+// brailleDots are the bits of the dots of a braille cell, indexed by row then column.
+var brailleDots = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+// renderBraille draws a 2x4 grid of pixels per cell, as dots where they are brighter than the
+// average of the cell, colored with the average of the dots.
+func renderBraille(w *writer, at func(x, y int) color.NRGBA, cols, rows int) {
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			var cell [4][2]color.NRGBA
+			var mean float64
+			var opaque int
+			for dy := 0; dy < 4; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					c := at(2*x+dx, 4*y+dy)
+					cell[dy][dx] = c
+					if !transparent(c) {
+						mean += luma(c)
+						opaque++
+					}
+				}
+			}
+			if opaque == 0 {
+				w.cell(nil, nil, ' ')
+				continue
+			}
+			mean /= float64(opaque)
+			// Without colors, dots follow the brightness alone, so uniform areas still show.
+			threshold := mean
+			if w.depth == NoColor {
+				threshold = 128
+			}
+
+			var dots rune
+			var r, g, b, n int
+			for dy := 0; dy < 4; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					c := cell[dy][dx]
+					if transparent(c) || luma(c) < threshold {
+						continue
+					}
+					dots |= brailleDots[dy][dx]
+					r, g, b, n = r+int(c.R), g+int(c.G), b+int(c.B), n+1
+				}
+			}
+			if n == 0 {
+				w.cell(nil, nil, ' ')
+				continue
+			}
+			fg := color.NRGBA{uint8(r / n), uint8(g / n), uint8(b / n), 255}
+			w.cell(&fg, nil, 0x2800+dots)
+		}
+		w.endLine()
+	}
+}
+
+// btoi returns 1 for true and 0 for false.
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// writer builds the art, only writing color codes when the colors change.
+type writer struct {
+	depth  ColorDepth
+	sb     strings.Builder
+	fg, bg string
+}
+
+// cell writes r with the given colors, nil being the terminal default.
+func (w *writer) cell(fg, bg *color.NRGBA, r rune) {
+	if w.depth != NoColor {
+		fgCode, bgCode := w.code(fg, 38), w.code(bg, 48)
+		if (fgCode == "" && w.fg != "") || (bgCode == "" && w.bg != "") {
+			w.sb.WriteString("\x1b[0m")
+			w.fg, w.bg = "", ""
+		}
+		if fgCode != w.fg {
+			w.sb.WriteString(fgCode)
+			w.fg = fgCode
+		}
+		if bgCode != w.bg {
+			w.sb.WriteString(bgCode)
+			w.bg = bgCode
+		}
+	}
+	w.sb.WriteRune(r)
+}
+
+// code returns the escape code setting c as the foreground (38) or background (48) color.
+func (w *writer) code(c *color.NRGBA, layer int) string {
+	if c == nil {
+		return ""
+	}
+	if w.depth == Color256 {
+		return fmt.Sprintf("\x1b[%d;5;%dm", layer, Index256(*c))
+	}
+	return fmt.Sprintf("\x1b[%d;2;%d;%d;%dm", layer, c.R, c.G, c.B)
+}
+
+// endLine resets the colors and ends the line.
+func (w *writer) endLine() {
+	if w.fg != "" || w.bg != "" {
+		w.sb.WriteString("\x1b[0m")
+		w.fg, w.bg = "", ""
+	}
+	w.sb.WriteByte('\n')
+}
+
+// String returns the art written so far.
+func (w *writer) String() string {
+	return w.sb.String()
+}
+
+// cubeLevels are the channel values of the 6x6x6 color cube of the xterm palette.
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// Index256 returns the xterm 256 color palette index closest to c, from its color cube or gray ramp.
+func Index256(c color.NRGBA) int {
+	nearest := func(v uint8) int {
+		best := 0
+		for i, level := range cubeLevels {
+			if abs(int(v)-level) < abs(int(v)-cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	r, g, b := nearest(c.R), nearest(c.G), nearest(c.B)
+	cube := 16 + 36*r + 6*g + b
+	cubeDistance := distance(c, cubeLevels[r], cubeLevels[g], cubeLevels[b])
+
+	// The gray ramp goes from 8 to 238 in steps of 10.
+	avg := (int(c.R) + int(c.G) + int(c.B)) / 3
+	gray := min(23, max(0, (avg-3)/10))
+	level := 8 + 10*gray
+	if distance(c, level, level, level) < cubeDistance {
+		return 232 + gray
+	}
+	return cube
+}
+
+// distance returns the squared distance between c and an RGB color.
+func distance(c color.NRGBA, r, g, b int) int {
+	dr, dg, db := int(c.R)-r, int(c.G)-g, int(c.B)-b
+	return dr*dr + dg*dg + db*db
+}
+
+// abs returns the absolute value of v.
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// Export renders img to path: plain text for ".txt" whatever the color depth of opts,
+// colored ANSI art otherwise, like ".ans".
+func Export(path string, img image.Image, opts Options) error {
+	if strings.EqualFold(filepath.Ext(path), ".txt") {
+		opts.Color = NoColor
+	}
+	return os.WriteFile(path, []byte(Render(img, opts)), 0644)
+}
@@ -0,0 +1,113 @@
+package termimage
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// splitImage is white on the left half and black on the right half.
+func splitImage(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.NRGBA{A: 255}
+			if x < w/2 {
+				c = color.NRGBA{255, 255, 255, 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestSize(t *testing.T) {
+	cols, rows := Size(image.Pt(100, 100), Options{Width: 40})
+	assert.Equal(t, 40, cols)
+	assert.Equal(t, 20, rows, "cells are twice as tall as wide")
+
+	cols, rows = Size(image.Pt(100, 100), Options{Width: 40, MaxHeight: 10})
+	assert.Equal(t, 20, cols, "the width shrinks to keep the aspect ratio")
+	assert.Equal(t, 10, rows)
+
+	cols, rows = Size(image.Pt(0, 10), Options{Width: 40})
+	assert.Zero(t, cols)
+	assert.Zero(t, rows)
+}
+
+func TestRenderASCII(t *testing.T) {
+	out := Render(splitImage(4, 4), Options{Mode: ASCII, Color: NoColor, Width: 4})
+	assert.Equal(t, "@@  \n@@  \n", out)
+}
+
+func TestRenderHalfBlocks(t *testing.T) {
+	img := splitImage(2, 2)
+	img.SetNRGBA(0, 1, color.NRGBA{})
+
+	out := Render(img, Options{Mode: HalfBlocks, Width: 2})
+	assert.Equal(t, "\x1b[38;2;255;255;255m▀\x1b[38;2;0;0;0m\x1b[48;2;0;0;0m▀\x1b[0m\n", out)
+
+	out = Render(img, Options{Mode: HalfBlocks, Color: NoColor, Width: 2})
+	assert.Equal(t, "▀ \n", out)
+}
+
+func TestRenderBraille(t *testing.T) {
+	// The left column of dots is bright, the right one dark.
+	out := Render(splitImage(2, 4), Options{Mode: Braille, Color: NoColor, Width: 1})
+	assert.Equal(t, string(rune(0x2800|0x01|0x02|0x04|0x40))+"\n", out)
+
+	out = Render(splitImage(2, 4), Options{Mode: Braille, Color: Color256, Width: 1})
+	assert.True(t, strings.HasPrefix(out, "\x1b[38;5;231m"), out)
+}
+
+func TestRenderTransparent(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for _, mode := range Modes {
+		out := Render(img, Options{Mode: mode, Width: 2})
+		assert.NotContains(t, out, "\x1b", mode)
+		assert.Equal(t, strings.Repeat("  \n", strings.Count(out, "\n")), out, mode)
+	}
+
+	out := Render(img, Options{Mode: HalfBlocks, Width: 2, Checkerboard: true})
+	assert.Contains(t, out, "\x1b[38;2;153;153;153m")
+}
+
+func TestIndex256(t *testing.T) {
+	assert.Equal(t, 16, Index256(color.NRGBA{A: 255}))
+	assert.Equal(t, 231, Index256(color.NRGBA{255, 255, 255, 255}))
+	assert.Equal(t, 196, Index256(color.NRGBA{255, 0, 0, 255}))
+	assert.Equal(t, 244, Index256(color.NRGBA{128, 128, 128, 255}))
+}
+
+func TestParseMode(t *testing.T) {
+	for _, m := range Modes {
+		parsed, err := ParseMode(m.String())
+		assert.NoError(t, err)
+		assert.Equal(t, m, parsed)
+	}
+	_, err := ParseMode("sixel")
+	assert.Error(t, err)
+	assert.Equal(t, HalfBlocks, Braille.Next())
+}
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{Mode: HalfBlocks, Width: 4}
+
+	txt := filepath.Join(dir, "art.txt")
+	assert.NoError(t, Export(txt, splitImage(8, 8), opts))
+	data, err := os.ReadFile(txt)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "\x1b", "text exports have no colors")
+
+	ans := filepath.Join(dir, "art.ans")
+	assert.NoError(t, Export(ans, splitImage(8, 8), opts))
+	data, err = os.ReadFile(ans)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "\x1b[38;2;255;255;255m")
+}
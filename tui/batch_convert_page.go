@@ -0,0 +1,225 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sterben/features/image_convert"
+	"sterben/pkg/pages"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+// batchConvertPageRows is the number of files shown at once on the batch convert page.
+const batchConvertPageRows = 12
+
+// BatchConvertPageModel represents the model for the "Batch Convert" page.
+// It converts every image matched by a list of directories, globs and paths.
+type BatchConvertPageModel struct {
+	Cfg        *pages.ModelConfig
+	Input      textinput.Model
+	InputError string
+	Output     int // Index of the selected output in iconOutputs.
+	Items      []image_convert.BatchItem
+	Running    bool
+	Finished   bool
+	Elapsed    time.Duration
+	Time       time.Time
+
+	itemsMutex sync.Mutex
+}
+
+// BatchConvertPage initializes a new BatchConvertPageModel with the provided configuration.
+func BatchConvertPage(cfg *pages.ModelConfig) *BatchConvertPageModel {
+	m := &BatchConvertPageModel{
+		Cfg:  cfg,
+		Time: time.Now(),
+	}
+
+	// Initialize the text input with styles
+	input := textinput.New()
+	input.Placeholder = "Enter directories, globs or paths, separated by commas"
+	input.Width = 60
+	input.Focus()
+
+	redStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff1f1f"))
+	input.Cursor.Style = lipgloss.NewStyle().Background(lipgloss.Color("#ff1f1f"))
+	input.Cursor.TextStyle = redStyle
+	input.TextStyle = redStyle
+	input.CompletionStyle = redStyle
+	input.PlaceholderStyle = redStyle
+	input.PromptStyle = redStyle
+
+	m.Input = input
+	return m
+}
+
+// Init initializes the model, setting up the blinking cursor for text input.
+func (p *BatchConvertPageModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, tick())
+}
+
+// Update handles incoming messages and updates the model state accordingly.
+func (p *BatchConvertPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	// Update the time
+	switch msg := msg.(type) {
+	case tickMsg:
+		p.Time = time.Time(msg)
+		cmds = append(cmds, tick())
+	}
+
+	// Update text input
+	ti, cmd := p.Input.Update(msg)
+	p.Input = ti
+	cmds = append(cmds, cmd)
+
+	// Handle key messages
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			return p.Cfg.Pages.SwitchToPreviousModel()
+		case tea.KeyTab:
+			p.Output = (p.Output + 1) % len(iconOutputs)
+		case tea.KeyEnter:
+			if p.Running {
+				return p, tea.Batch(cmds...)
+			}
+
+			p.InputError = ""
+			paths, err := image_convert.ExpandInputs(strings.Split(p.Input.Value(), ","))
+			if err != nil {
+				p.InputError = err.Error()
+				return p, tea.Batch(cmds...)
+			}
+			if len(paths) == 0 {
+				p.InputError = "No images found"
+				return p, tea.Batch(cmds...)
+			}
+
+			p.start(paths)
+		}
+	}
+
+	return p, tea.Batch(cmds...)
+}
+
+// start converts paths in a goroutine, the tick refreshes the progress list.
+func (p *BatchConvertPageModel) start(paths []string) {
+	opts := image_convert.DefaultOptions()
+	opts.Formats = []image_convert.OutputFormat{iconOutputs[p.Output].Format}
+
+	p.itemsMutex.Lock()
+	p.Items = make([]image_convert.BatchItem, len(paths))
+	for i, path := range paths {
+		p.Items[i] = image_convert.BatchItem{Path: path}
+	}
+	p.itemsMutex.Unlock()
+
+	p.Running = true
+	p.Finished = false
+	p.Elapsed = 0
+	go func() {
+		start := time.Now()
+		report := image_convert.ConvertBatch(paths, opts, 0, func(index int, item image_convert.BatchItem) {
+			p.itemsMutex.Lock()
+			p.Items[index] = item
+			p.itemsMutex.Unlock()
+		})
+
+		for _, item := range report.Failed() {
+			p.Cfg.Log.Error().Err(item.Err).Str("path", item.Path).Msg("Failed to convert image")
+		}
+		p.Cfg.Log.Info().Int("converted", report.Succeeded()).Int("failed", len(report.Failed())).Msg("Finished batch conversion")
+
+		p.Elapsed = time.Since(start)
+		p.Running = false
+		p.Finished = true
+	}()
+}
+
+// View renders the UI for the BatchConvertPageModel.
+func (p *BatchConvertPageModel) View() string {
+	w, h, _ := term.GetSize(int(os.Stdout.Fd()))
+
+	// Title
+	title := lipgloss.NewStyle().Bold(true).Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(BatchConvert.Name)
+
+	// Input
+	input := p.Input.View()
+	output := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("Output: " + iconOutputs[p.Output].Name + "  (tab)")
+
+	// Progress list
+	p.itemsMutex.Lock()
+	items := make([]image_convert.BatchItem, len(p.Items))
+	copy(items, p.Items)
+	p.itemsMutex.Unlock()
+
+	var list string
+	var done, failed int
+	for _, item := range items {
+		switch item.Status {
+		case image_convert.BatchDone:
+			done++
+		case image_convert.BatchFailed:
+			failed++
+		}
+	}
+	start := 0
+	if len(items) > batchConvertPageRows {
+		// Keep the files being converted in view.
+		start = min(done+failed, len(items)-batchConvertPageRows)
+	}
+	for _, item := range items[start:min(len(items), start+batchConvertPageRows)] {
+		status := item.Status.String()
+		switch item.Status {
+		case image_convert.BatchDone:
+			status = fmt.Sprintf("done, %d files in %s", len(item.Result.Files), item.Duration.Round(time.Millisecond))
+		case image_convert.BatchFailed:
+			status = "failed: " + item.Err.Error()
+		}
+		list += truncate(fmt.Sprintf("%-30s %s", truncate(filepath.Base(item.Path), 30), status), 100) + "\n"
+	}
+	list = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Render(list)
+
+	// Summary
+	var summary string
+	switch {
+	case p.Running:
+		summary = fmt.Sprintf("Converting... %d/%d", done+failed, len(items))
+	case p.Finished:
+		summary = fmt.Sprintf("Converted %d of %d images in %s, %d failed", done, len(items), p.Elapsed.Round(time.Millisecond), failed)
+	}
+	summary = lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(summary)
+
+	// Error handling
+	err := lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(p.InputError)
+
+	style := lipgloss.NewStyle().
+		Width(w).
+		Height(h).
+		Align(lipgloss.Center, lipgloss.Center)
+
+	return style.Render(fmt.Sprintf("%s\n%s\n%s\n\n%s\n%s\n%s", title, input, output, list, summary, err))
+}
+
+// Reset clears the input and the previous batch, unless a batch is running.
+func (p *BatchConvertPageModel) Reset() {
+	if p.Running {
+		return
+	}
+	p.Input.Reset()
+	p.InputError = ""
+	p.itemsMutex.Lock()
+	p.Items = nil
+	p.itemsMutex.Unlock()
+	p.Finished = false
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"sterben/features/youtube"
+	"sterben/pkg/clipboard"
+	"sterben/pkg/config"
+	"sterben/pkg/pages"
+	youtubeTui "sterben/tui/youtube"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clipboardToastTimeout is how long a clipboard URL is offered before the toast disappears.
+const clipboardToastTimeout = 15 * time.Second
+
+// watchClipboard starts the clipboard watcher if it is enabled in the config and
+// offers every new supported URL in a toast. It returns nil if the watcher is disabled.
+func (y *Tui) watchClipboard(program *tea.Program) *clipboard.Watcher {
+	cfg, err := config.GetConfig()
+	if err != nil || !cfg.Clipboard.Watch {
+		return nil
+	}
+
+	interval := time.Duration(cfg.Clipboard.IntervalMs) * time.Millisecond
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	w := clipboard.NewWatcher(clipboard.NewSystem(), interval, youtube.ExtractURL)
+	w.Start(func(url string) {
+		y.Log.Info().Str("url", url).Msg("Detected URL on clipboard")
+		program.Send(pages.ShowToastMsg(y.clipboardToast(url)))
+	}, func(err error) {
+		y.Log.Debug().Err(err).Msg("Failed to read clipboard")
+	})
+
+	return w
+}
+
+// clipboardToast returns the toast offering to fetch metadata for or queue the download of url.
+func (y *Tui) clipboardToast(url string) pages.Toast {
+	return pages.Toast{
+		Message: "Copied " + url,
+		Timeout: clipboardToastTimeout,
+		Actions: []pages.ToastAction{
+			{
+				Key:   "ctrl+f",
+				Label: "fetch metadata",
+				Run: func() tea.Cmd {
+					setUrlPageModel := y.Pages.Models[youtubeTui.SetUrl].(*youtubeTui.SetUrlPageModel)
+					setUrlPageModel.Reset()
+					setUrlPageModel.Input.SetValue(url)
+					setUrlPageModel.LoadMetaData()
+
+					// Return to the youtube home page once the metadata is loaded.
+					_, homeCmd := y.Pages.SwitchModel(youtubeTui.Home)
+					_, setUrlCmd := y.Pages.SwitchModel(youtubeTui.SetUrl)
+					return tea.Batch(homeCmd, setUrlCmd)
+				},
+			},
+			{
+				Key:   "ctrl+q",
+				Label: "queue download",
+				Run: func() tea.Cmd {
+					homePageModel := y.Pages.Models[youtubeTui.Home].(*youtubeTui.HomePageModel)
+					homePageModel.Enqueue(url)
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,218 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"sterben/features/image_convert"
+	"sterben/pkg/pages"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+// formatWidths are the output widths offered on the format convert page, 0 keeping the original.
+var formatWidths = []int{0, 3840, 1920, 1280, 640, 256}
+
+// FormatConvertPageModel represents the model for the "Convert Image Format" page.
+// It converts an image to PNG, JPEG, GIF, BMP or TIFF next to the original.
+type FormatConvertPageModel struct {
+	Cfg        *pages.ModelConfig
+	Input      textinput.Model
+	InputError string
+	Options    image_convert.FormatOptions
+	Width      int // Index of the selected width in formatWidths.
+	Converting bool
+	Alert      string
+	Time       time.Time
+}
+
+// FormatConvertPage initializes a new FormatConvertPageModel with the provided configuration.
+func FormatConvertPage(cfg *pages.ModelConfig) *FormatConvertPageModel {
+	m := &FormatConvertPageModel{
+		Cfg: cfg,
+		Options: image_convert.FormatOptions{
+			Format:  image_convert.ImagePNG,
+			Quality: image_convert.DefaultJPEGQuality,
+			Colors:  image_convert.DefaultGIFColors,
+			Resize:  image_convert.DefaultResizeOptions,
+		},
+		Time: time.Now(),
+	}
+
+	// Initialize the text input with styles
+	input := textinput.New()
+	input.Placeholder = "Enter Image Path"
+	input.Focus()
+
+	redStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff1f1f"))
+	input.Cursor.Style = lipgloss.NewStyle().Background(lipgloss.Color("#ff1f1f"))
+	input.Cursor.TextStyle = redStyle
+	input.TextStyle = redStyle
+	input.CompletionStyle = redStyle
+	input.PlaceholderStyle = redStyle
+	input.PromptStyle = redStyle
+
+	m.Input = input
+	return m
+}
+
+// Init initializes the model, setting up the blinking cursor for text input.
+func (p *FormatConvertPageModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, tick())
+}
+
+// Update handles incoming messages and updates the model state accordingly.
+func (p *FormatConvertPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	// Update the time
+	switch msg := msg.(type) {
+	case tickMsg:
+		p.Time = time.Time(msg)
+		cmds = append(cmds, tick())
+	}
+
+	// Update text input
+	ti, cmd := p.Input.Update(msg)
+	p.Input = ti
+	cmds = append(cmds, cmd)
+
+	// Handle key messages
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			return p.Cfg.Pages.SwitchToPreviousModel()
+		case tea.KeyTab:
+			p.Options.Format = p.Options.Format.Next()
+		case tea.KeyUp:
+			switch p.Options.Format {
+			case image_convert.ImageJPEG:
+				p.Options.Quality = min(100, p.Options.Quality+5)
+			case image_convert.ImageGIF:
+				p.Options.Colors = min(256, p.Options.Colors*2)
+			}
+		case tea.KeyDown:
+			switch p.Options.Format {
+			case image_convert.ImageJPEG:
+				p.Options.Quality = max(5, p.Options.Quality-5)
+			case image_convert.ImageGIF:
+				p.Options.Colors = max(2, p.Options.Colors/2)
+			}
+		case tea.KeyCtrlP:
+			p.Options.Palette = p.Options.Palette.Next()
+		case tea.KeyCtrlG:
+			p.Options.Dither = !p.Options.Dither
+		case tea.KeyCtrlR:
+			p.Width = (p.Width + 1) % len(formatWidths)
+		case tea.KeyCtrlS:
+			p.Options.StripMetadata = !p.Options.StripMetadata
+		case tea.KeyEnter:
+			p.convert()
+		}
+	}
+
+	return p, tea.Batch(cmds...)
+}
+
+// convert converts the image at the input path in a goroutine, the tick shows the result.
+func (p *FormatConvertPageModel) convert() {
+	p.InputError = ""
+	p.Alert = ""
+	if p.Converting {
+		return
+	}
+	if p.Input.Value() == "" {
+		p.InputError = "Please enter a valid Path"
+		return
+	}
+
+	path := p.Input.Value()
+	opts := p.Options
+	opts.Width = formatWidths[p.Width]
+
+	p.Converting = true
+	go func() {
+		defer func() { p.Converting = false }()
+
+		output, err := image_convert.ConvertFormat(path, "", opts)
+		if err != nil {
+			p.Cfg.Log.Error().Err(err).Str("input", path).Msg("Failed to convert image")
+			p.InputError = err.Error()
+			return
+		}
+		p.Cfg.Log.Info().Str("input", path).Str("output", output).Msg("Converted image")
+
+		p.Alert = "Wrote " + output
+		if in, err := os.Stat(path); err == nil {
+			if out, err := os.Stat(output); err == nil {
+				p.Alert += fmt.Sprintf(" (%s -> %s)", formatSize(in.Size()), formatSize(out.Size()))
+			}
+		}
+	}()
+}
+
+// View renders the UI for the FormatConvertPageModel.
+func (p *FormatConvertPageModel) View() string {
+	w, h, _ := term.GetSize(int(os.Stdout.Fd()))
+
+	// Title
+	title := lipgloss.NewStyle().Bold(true).Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(FormatConvert.Name)
+
+	// Input
+	var input string
+	if p.Converting {
+		input = lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render("Converting...")
+	} else {
+		input = p.Input.View()
+	}
+
+	// Options
+	format := "Format: " + string(p.Options.Format) + "  (tab)"
+	switch p.Options.Format {
+	case image_convert.ImageJPEG:
+		format += fmt.Sprintf("\nQuality: %d  (up/down)", p.Options.Quality)
+	case image_convert.ImageGIF:
+		dither := "off"
+		if p.Options.Dither {
+			dither = "on"
+		}
+		format += fmt.Sprintf("\nPalette: %s  (ctrl+p)  |  Dither: %s  (ctrl+g)", p.Options.Palette, dither)
+		if p.Options.Palette == image_convert.PaletteAdaptive {
+			format += fmt.Sprintf("  |  Colors: %d  (up/down)", p.Options.Colors)
+		}
+	}
+	width := "original"
+	if formatWidths[p.Width] > 0 {
+		width = fmt.Sprintf("%dpx wide", formatWidths[p.Width])
+	}
+	metadata := "keep"
+	if p.Options.StripMetadata {
+		metadata = "strip"
+	}
+	options := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render(fmt.Sprintf(
+		"%s\nSize: %s  (ctrl+r)  |  Metadata: %s  (ctrl+s)", format, width, metadata))
+
+	// Alert and error handling
+	alert := lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(p.Alert)
+	if p.InputError != "" {
+		alert = lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(p.InputError)
+	}
+
+	style := lipgloss.NewStyle().
+		Width(w).
+		Height(h).
+		Align(lipgloss.Center, lipgloss.Center)
+
+	return style.Render(fmt.Sprintf("%s\n%s\n%s\n%s", title, input, options, alert))
+}
+
+// Reset clears the input and the last result.
+func (p *FormatConvertPageModel) Reset() {
+	p.Input.Reset()
+	p.InputError = ""
+	p.Alert = ""
+}
@@ -30,6 +30,14 @@ func HomePage(cfg *pages.ModelConfig) *HomePageModel {
 	m.Options.List = []pages.PageType{
 		Youtube,
 		ImageToIcon,
+		BatchConvert,
+		IconInspector,
+		FormatConvert,
+		Palette,
+		TerminalArt,
+		Transcode,
+		Library,
+		Retention,
 	}
 
 	m.Options.Cursor = m.Options.List[0]
@@ -128,6 +136,27 @@ func (p *HomePageModel) handleEnter() (tea.Model, tea.Cmd) {
 		return p.Cfg.Pages.SwitchModel(youtube.Home)
 	case ImageToIcon:
 		return p.Cfg.Pages.SwitchModel(ImageToIcon)
+	case BatchConvert:
+		p.Cfg.Pages.Models[BatchConvert].(*BatchConvertPageModel).Reset()
+		return p.Cfg.Pages.SwitchModel(BatchConvert)
+	case IconInspector:
+		return p.Cfg.Pages.SwitchModel(IconInspector)
+	case FormatConvert:
+		return p.Cfg.Pages.SwitchModel(FormatConvert)
+	case Palette:
+		return p.Cfg.Pages.SwitchModel(Palette)
+	case TerminalArt:
+		return p.Cfg.Pages.SwitchModel(TerminalArt)
+	case Transcode:
+		transcodePageModel := p.Cfg.Pages.Models[Transcode].(*TranscodePageModel)
+		if !transcodePageModel.TranscodeLoading {
+			transcodePageModel.Reset()
+		}
+		return p.Cfg.Pages.SwitchModel(Transcode)
+	case Library:
+		return p.Cfg.Pages.SwitchModel(Library)
+	case Retention:
+		return p.Cfg.Pages.SwitchModel(Retention)
 	default:
 		return p, nil
 	}
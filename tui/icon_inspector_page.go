@@ -0,0 +1,216 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sterben/features/image_convert"
+	"sterben/pkg/pages"
+	"sterben/pkg/termimage"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+// iconPreviewMaxSize is the largest preview drawn, in terminal columns.
+const iconPreviewMaxSize = 48
+
+// IconInspectorPageModel represents the model for the "Icon Inspector" page.
+// It lists the entries of an ICO or CUR file, previews them and extracts them as PNGs.
+type IconInspectorPageModel struct {
+	Cfg        *pages.ModelConfig
+	Input      textinput.Model
+	InputError string
+	Path       string
+	File       *image_convert.IcoFile
+	Cursor     int
+	Alert      string
+	Time       time.Time
+}
+
+// IconInspectorPage initializes a new IconInspectorPageModel with the provided configuration.
+func IconInspectorPage(cfg *pages.ModelConfig) *IconInspectorPageModel {
+	m := &IconInspectorPageModel{
+		Cfg:  cfg,
+		Time: time.Now(),
+	}
+
+	// Initialize the text input with styles
+	input := textinput.New()
+	input.Placeholder = "Enter ICO or CUR Path"
+	input.Focus()
+
+	redStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff1f1f"))
+	input.Cursor.Style = lipgloss.NewStyle().Background(lipgloss.Color("#ff1f1f"))
+	input.Cursor.TextStyle = redStyle
+	input.TextStyle = redStyle
+	input.CompletionStyle = redStyle
+	input.PlaceholderStyle = redStyle
+	input.PromptStyle = redStyle
+
+	m.Input = input
+	return m
+}
+
+// Init initializes the model, setting up the blinking cursor for text input.
+func (p *IconInspectorPageModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, tick())
+}
+
+// Update handles incoming messages and updates the model state accordingly.
+func (p *IconInspectorPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	// Update the time
+	switch msg := msg.(type) {
+	case tickMsg:
+		p.Time = time.Time(msg)
+		cmds = append(cmds, tick())
+	}
+
+	// Update text input
+	ti, cmd := p.Input.Update(msg)
+	p.Input = ti
+	cmds = append(cmds, cmd)
+
+	// Handle key messages
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			return p.Cfg.Pages.SwitchToPreviousModel()
+		case tea.KeyUp:
+			if p.Cursor > 0 {
+				p.Cursor--
+			}
+		case tea.KeyDown:
+			if p.File != nil && p.Cursor < len(p.File.Entries)-1 {
+				p.Cursor++
+			}
+		case tea.KeyEnter:
+			p.load()
+		case tea.KeyCtrlE:
+			p.extract()
+		}
+	}
+
+	return p, tea.Batch(cmds...)
+}
+
+// load decodes the file at the input path.
+func (p *IconInspectorPageModel) load() {
+	p.InputError = ""
+	p.Alert = ""
+	if p.Input.Value() == "" {
+		p.InputError = "Please enter a valid Path"
+		return
+	}
+
+	f, err := os.Open(p.Input.Value())
+	if err != nil {
+		p.InputError = err.Error()
+		return
+	}
+	defer f.Close()
+
+	file, err := image_convert.DecodeIco(f)
+	if err != nil {
+		p.Cfg.Log.Error().Err(err).Str("path", p.Input.Value()).Msg("Failed to decode icon")
+		p.InputError = err.Error()
+		return
+	}
+
+	p.Path = p.Input.Value()
+	p.File = file
+	p.Cursor = 0
+}
+
+// extract writes every entry of the loaded file as a PNG next to it.
+func (p *IconInspectorPageModel) extract() {
+	if p.File == nil {
+		return
+	}
+
+	outputDir := strings.TrimSuffix(p.Path, filepath.Ext(p.Path)) + "-extracted"
+	paths, err := image_convert.ExtractIco(p.Path, outputDir)
+	if err != nil {
+		p.Cfg.Log.Error().Err(err).Str("path", p.Path).Msg("Failed to extract icon")
+		p.InputError = err.Error()
+		return
+	}
+
+	p.Cfg.Log.Info().Str("path", p.Path).Int("entries", len(paths)).Msg("Extracted icon")
+	p.Alert = fmt.Sprintf("Extracted %d images to %s", len(paths), outputDir)
+}
+
+// View renders the UI for the IconInspectorPageModel.
+func (p *IconInspectorPageModel) View() string {
+	w, h, _ := term.GetSize(int(os.Stdout.Fd()))
+
+	// Title
+	title := lipgloss.NewStyle().Bold(true).Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(IconInspector.Name)
+
+	// Entries
+	var entries, preview string
+	if p.File != nil {
+		kind := "Icon"
+		if p.File.Cursor {
+			kind = "Cursor"
+		}
+		entries = fmt.Sprintf("%s with %d entries\n\n", kind, len(p.File.Entries))
+		for i, entry := range p.File.Entries {
+			if i == p.Cursor {
+				entries += "> "
+			} else {
+				entries += "  "
+			}
+			storage := "BMP"
+			if entry.PNG {
+				storage = "PNG"
+			}
+			entries += fmt.Sprintf("%4dx%-4d %2d bpp  %s  %6d bytes", entry.Width, entry.Height, entry.BitCount, storage, entry.DataSize)
+			if p.File.Cursor {
+				entries += fmt.Sprintf("  hotspot %d,%d", entry.Hotspot.X, entry.Hotspot.Y)
+			}
+			entries += "\n"
+		}
+		entries = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Render(entries)
+
+		if p.Cursor < len(p.File.Entries) {
+			img := p.File.Entries[p.Cursor].Image
+			preview = termimage.Render(img, termimage.Options{
+				Width:        min(iconPreviewMaxSize, w-4, img.Bounds().Dx()),
+				Checkerboard: true,
+			})
+		}
+	}
+
+	// Alert and error handling
+	alert := lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(p.Alert)
+	if p.InputError != "" {
+		alert = lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(p.InputError)
+	}
+
+	help := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("enter: load  up/down: select  ctrl+e: extract as png  esc: back")
+
+	style := lipgloss.NewStyle().
+		Width(w).
+		Height(h).
+		Align(lipgloss.Center, lipgloss.Center)
+
+	return style.Render(fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s", title, p.Input.View(), entries, preview, alert, help))
+}
+
+// Reset clears the input and the loaded file.
+func (p *IconInspectorPageModel) Reset() {
+	p.Input.Reset()
+	p.InputError = ""
+	p.Path = ""
+	p.File = nil
+	p.Cursor = 0
+	p.Alert = ""
+}
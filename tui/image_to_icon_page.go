@@ -2,9 +2,12 @@ package tui
 
 import (
 	"fmt"
+	"image"
 	"os"
 	"sterben/features/image_convert"
+	"sterben/pkg/config"
 	"sterben/pkg/pages"
+	"sterben/pkg/termimage"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -21,7 +24,82 @@ type ImageToIconPageModel struct {
 	InputError         string
 	ImageToIconError   string
 	ImageToIconLoading bool
+	Output             int // Index of the selected output in iconOutputs.
+	Hotspot            int // Index of the selected hotspot in cursorHotspots.
+	Resize             image_convert.ResizeOptions
+	EffectPresets      []config.EffectPresetConfig // Loaded from the config when the page opens.
+	EffectPreset       string                      // Name of the selected effect preset, "" for none.
 	Time               time.Time
+
+	// Size of the image at sourcePath, read when the path changes to show the resulting bounds.
+	sourcePath string
+	sourceSize image.Point
+
+	// Decoded image at previewPath, and the preview of the path and options in previewKey.
+	// Both are made by commands, so typing a path never waits for an image to decode.
+	previewPath   string
+	previewSource image.Image
+	previewKey    string
+	preview       string
+}
+
+// iconPreviewSize is the size in pixels of the icon previewed on the page, drawn iconPreviewWidth
+// columns wide, iconPreviewDelay after the path or options last changed.
+const (
+	iconPreviewSize  = 64
+	iconPreviewWidth = 32
+	iconPreviewDelay = 300 * time.Millisecond
+)
+
+// iconPreviewDueMsg is a custom message used to render the preview once the input settled.
+type iconPreviewDueMsg struct {
+	key string
+}
+
+// iconPreviewMsg is a custom message used to deliver a rendered preview and its decoded source.
+type iconPreviewMsg struct {
+	key     string
+	path    string
+	source  image.Image
+	preview string
+}
+
+// iconOutput is a kind of file the page can write.
+type iconOutput struct {
+	Name   string
+	Format image_convert.OutputFormat
+}
+
+// iconOutputs are the outputs offered on the page, the first being the default.
+var iconOutputs = append([]iconOutput{
+	{"single multi-size .ico", image_convert.FormatICO},
+	{"one .ico per size", image_convert.FormatICOPerSize},
+	{"one .png per size", image_convert.FormatPNG},
+	{"macOS .icns", image_convert.FormatICNS},
+	{"windows .cur cursor", image_convert.FormatCUR},
+	{"web favicon bundle", image_convert.FormatFavicon},
+}, iconSetOutputs()...)
+
+// iconSetOutputs returns an output for every platform icon set preset.
+func iconSetOutputs() []iconOutput {
+	var outputs []iconOutput
+	for _, preset := range image_convert.IconSetPresets {
+		outputs = append(outputs, iconOutput{preset.Name + " icon set", image_convert.OutputFormat(preset.Name)})
+	}
+	return outputs
+}
+
+// iconBackgrounds are the padding colors offered on the page, "" being transparent.
+var iconBackgrounds = []string{"", "#ffffff", "#000000"}
+
+// cursorHotspots are the cursor hotspots offered on the page.
+var cursorHotspots = []struct {
+	Name    string
+	Hotspot image_convert.Hotspot
+}{
+	{"top left", image_convert.Hotspot{X: 0, Y: 0, Normalized: true}},
+	{"center", image_convert.Hotspot{X: 0.5, Y: 0.5, Normalized: true}},
+	{"top center", image_convert.Hotspot{X: 0.5, Y: 0, Normalized: true}},
 }
 
 // ImageToIconPage initializes a new ImageToIconPageModel with the provided configuration.
@@ -29,6 +107,7 @@ func ImageToIconPage(cfg *pages.ModelConfig) *ImageToIconPageModel {
 	m := &ImageToIconPageModel{
 		Cfg:                cfg,
 		ImageToIconLoading: false,
+		Resize:             image_convert.DefaultResizeOptions,
 		Time:               time.Now(),
 	}
 
@@ -51,6 +130,7 @@ func ImageToIconPage(cfg *pages.ModelConfig) *ImageToIconPageModel {
 
 // Init initializes the model, setting up the blinking cursor for text input.
 func (p *ImageToIconPageModel) Init() tea.Cmd {
+	p.loadEffectPresets()
 	return tea.Batch(textinput.Blink, tick())
 }
 
@@ -63,6 +143,19 @@ func (p *ImageToIconPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tickMsg:
 		p.Time = time.Time(msg)
 		cmds = append(cmds, tick())
+	case iconPreviewDueMsg:
+		if msg.key == p.previewKey {
+			return p, p.renderPreview()
+		}
+		return p, nil
+	case iconPreviewMsg:
+		if msg.source != nil {
+			p.previewPath, p.previewSource = msg.path, msg.source
+		}
+		if msg.key == p.previewKey {
+			p.preview = msg.preview
+		}
+		return p, nil
 	}
 
 	// Update text input
@@ -79,6 +172,26 @@ func (p *ImageToIconPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.Type {
 			case tea.KeyCtrlC, tea.KeyEsc:
 				return p.Cfg.Pages.SwitchToPreviousModel()
+			case tea.KeyTab:
+				p.Output = (p.Output + 1) % len(iconOutputs)
+			case tea.KeyCtrlT:
+				p.Resize.Filter = p.Resize.Filter.Next()
+			case tea.KeyCtrlR:
+				p.Resize.Mode = p.Resize.Mode.Next()
+			case tea.KeyCtrlG:
+				p.Resize.Crop = p.Resize.Crop.Next()
+			case tea.KeyCtrlO:
+				p.Resize.Background, _ = image_convert.ParseColor(iconBackgrounds[(p.backgroundIndex()+1)%len(iconBackgrounds)])
+			case tea.KeyCtrlK:
+				p.Hotspot = (p.Hotspot + 1) % len(cursorHotspots)
+			case tea.KeyCtrlL:
+				p.EffectPreset = p.nextEffectPreset()
+			case tea.KeyCtrlS:
+				if p.Resize.SharpenMaxSize > 0 {
+					p.Resize.SharpenMaxSize = 0
+				} else {
+					p.Resize.SharpenMaxSize = 32
+				}
 			case tea.KeyEnter:
 				p.InputError = ""
 				if p.Input.Value() == "" {
@@ -88,12 +201,19 @@ func (p *ImageToIconPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				// Start loading metadata in a goroutine
 				p.ImageToIconLoading = true
+				opts := image_convert.DefaultOptions()
+				opts.Resize = p.Resize
+				opts.Formats = []image_convert.OutputFormat{iconOutputs[p.Output].Format}
+				opts.Hotspot = cursorHotspots[p.Hotspot].Hotspot
+				opts.Effects = p.effects()
 				go func() {
-					err := image_convert.ConvertImageToMultipleIcons(p.Input.Value(), image_convert.Sizes)
+					result, err := image_convert.Convert(p.Input.Value(), opts)
 					if err != nil {
 						p.ImageToIconError = err.Error()
 						p.ImageToIconLoading = false
+						return
 					}
+					p.Cfg.Log.Info().Str("input", p.Input.Value()).Strs("files", result.Files).Msg("Converted image to icons")
 				}()
 
 				return p, tea.Batch(func() tea.Msg {
@@ -105,6 +225,7 @@ func (p *ImageToIconPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return switchPrevPageMsg{}
 				})
 			}
+			cmds = append(cmds, p.schedulePreview())
 		} else {
 			// Handle non-focused input key messages
 			switch msg.Type {
@@ -135,6 +256,34 @@ func (p *ImageToIconPageModel) View() string {
 		input = p.Input.View()
 	}
 
+	// Output
+	output := "Output: " + iconOutputs[p.Output].Name
+	if iconOutputs[p.Output].Format == image_convert.FormatCUR {
+		output += fmt.Sprintf(", hotspot %s  (ctrl+k)", cursorHotspots[p.Hotspot].Name)
+	}
+	sharpen := "off"
+	if p.Resize.SharpenMaxSize > 0 {
+		sharpen = fmt.Sprintf("<= %dpx", p.Resize.SharpenMaxSize)
+	}
+	mode := p.Resize.Mode.String()
+	switch p.Resize.Mode {
+	case image_convert.Contain:
+		background := iconBackgrounds[p.backgroundIndex()]
+		if background == "" {
+			background = "transparent"
+		}
+		mode += fmt.Sprintf(", %s padding  (ctrl+o)", background)
+	case image_convert.Cover:
+		mode += fmt.Sprintf(", %s crop  (ctrl+g)", p.Resize.Crop)
+	}
+	effects := p.EffectPreset
+	if effects == "" {
+		effects = "none"
+	}
+	output = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render(fmt.Sprintf(
+		"%s  (tab)\nFilter: %s  (ctrl+t)  |  Sharpen: %s  (ctrl+s)\nMode: %s  (ctrl+r)\nEffects: %s  (ctrl+l)\n%s",
+		output, p.Resize.Filter, sharpen, mode, effects, p.bounds()))
+
 	// Error handling
 	err := lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(p.InputError)
 	if p.ImageToIconError != "" {
@@ -146,7 +295,140 @@ func (p *ImageToIconPageModel) View() string {
 		Height(h).
 		Align(lipgloss.Center, lipgloss.Center)
 
-	return style.Render(fmt.Sprintf("%s\n%s\n%s", title, input, err))
+	return style.Render(fmt.Sprintf("%s\n%s\n%s\n%s%s", title, input, output, p.preview, err))
+}
+
+// loadEffectPresets reads the effect presets from the config, keeping the selection if it still exists.
+func (p *ImageToIconPageModel) loadEffectPresets() {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		p.Cfg.Log.Error().Err(err).Msg("Failed to load effect presets")
+		return
+	}
+	p.EffectPresets = cfg.Image.EffectPresets
+
+	for _, preset := range p.EffectPresets {
+		if preset.Name == p.EffectPreset {
+			return
+		}
+	}
+	p.EffectPreset = ""
+}
+
+// nextEffectPreset returns the name of the preset after the selected one, "" after the last.
+func (p *ImageToIconPageModel) nextEffectPreset() string {
+	if p.EffectPreset == "" {
+		if len(p.EffectPresets) == 0 {
+			return ""
+		}
+		return p.EffectPresets[0].Name
+	}
+	for i, preset := range p.EffectPresets {
+		if preset.Name == p.EffectPreset && i+1 < len(p.EffectPresets) {
+			return p.EffectPresets[i+1].Name
+		}
+	}
+	return ""
+}
+
+// effects returns the steps of the selected effect preset.
+func (p *ImageToIconPageModel) effects() []image_convert.Effect {
+	for _, preset := range p.EffectPresets {
+		if preset.Name == p.EffectPreset {
+			return iconEffects(preset)
+		}
+	}
+	return nil
+}
+
+// iconEffects converts an effect preset from the config into effects.
+func iconEffects(preset config.EffectPresetConfig) []image_convert.Effect {
+	effects := make([]image_convert.Effect, len(preset.Effects))
+	for i, e := range preset.Effects {
+		effects[i] = image_convert.Effect{
+			Type:    image_convert.EffectType(e.Type),
+			Radius:  e.Radius,
+			Amount:  e.Amount,
+			Color:   e.Color,
+			OffsetX: e.OffsetX,
+			OffsetY: e.OffsetY,
+		}
+	}
+	return effects
+}
+
+// backgroundIndex returns the index of the current padding color in iconBackgrounds.
+func (p *ImageToIconPageModel) backgroundIndex() int {
+	for i, background := range iconBackgrounds {
+		c, _ := image_convert.ParseColor(background)
+		if c == p.Resize.Background {
+			return i
+		}
+	}
+	return 0
+}
+
+// bounds describes where the image ends up in the largest icon for the current resize options.
+func (p *ImageToIconPageModel) bounds() string {
+	path := p.Input.Value()
+	if path != p.sourcePath {
+		p.sourcePath = path
+		p.sourceSize, _ = image_convert.ImageSize(path)
+	}
+	if p.sourceSize == (image.Point{}) {
+		return ""
+	}
+
+	size := image_convert.Sizes[len(image_convert.Sizes)-1]
+	src, dst := image_convert.FitRect(p.sourceSize, size, p.Resize.Mode)
+	crop := fmt.Sprintf("%dx%d at %d,%d", src.Dx(), src.Dy(), src.Min.X, src.Min.Y)
+	if p.Resize.Mode == image_convert.Cover && p.Resize.Crop == image_convert.CropSmart {
+		crop = fmt.Sprintf("%dx%d", src.Dx(), src.Dy())
+	}
+
+	return fmt.Sprintf("%dx%d: uses %s, drawn %dx%d at %d,%d in %dx%d",
+		p.sourceSize.X, p.sourceSize.Y, crop, dst.Dx(), dst.Dy(), dst.Min.X, dst.Min.Y, size.X, size.Y)
+}
+
+// schedulePreview returns a command asking for the preview to be rendered once the path and
+// options stayed the same for iconPreviewDelay, or nil if they didn't change.
+func (p *ImageToIconPageModel) schedulePreview() tea.Cmd {
+	key := fmt.Sprintf("%s|%+v|%s", p.Input.Value(), p.Resize, p.EffectPreset)
+	if key == p.previewKey {
+		return nil
+	}
+	p.previewKey = key
+	return tea.Tick(iconPreviewDelay, func(time.Time) tea.Msg {
+		return iconPreviewDueMsg{key}
+	})
+}
+
+// renderPreview returns a command drawing the icon the current options make from the image
+// at the input path, decoding the image only when the path changed.
+func (p *ImageToIconPageModel) renderPreview() tea.Cmd {
+	key, path, resize, effects := p.previewKey, p.Input.Value(), p.Resize, p.effects()
+	source := p.previewSource
+	if path != p.previewPath {
+		source = nil
+	}
+
+	return func() tea.Msg {
+		if source == nil {
+			img, err := image_convert.DecodeFile(path)
+			if err != nil {
+				return iconPreviewMsg{key: key}
+			}
+			source = img
+		}
+
+		icon, err := image_convert.ApplyEffects(image_convert.Resize(source, image.Pt(iconPreviewSize, iconPreviewSize), resize), effects)
+		if err != nil {
+			p.Cfg.Log.Error().Err(err).Str("path", path).Msg("Failed to preview effects")
+			return iconPreviewMsg{key: key, path: path, source: source}
+		}
+		preview := "\n" + termimage.Render(icon, termimage.Options{Width: iconPreviewWidth, Checkerboard: true})
+		return iconPreviewMsg{key: key, path: path, source: source, preview: preview}
+	}
 }
 
 // Reset clears the input, metadata, and error states, resetting the page to its initial state.
@@ -155,6 +437,8 @@ func (p *ImageToIconPageModel) Reset() {
 	p.ImageToIconError = ""
 	p.ImageToIconLoading = false
 	p.InputError = ""
+	p.previewKey = ""
+	p.preview = ""
 }
 
 // tickMsg is a custom message used to update the time every second.
@@ -0,0 +1,392 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sterben/features/library"
+	"sterben/features/playlist"
+	"sterben/pkg/config"
+	"sterben/pkg/pages"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+// libraryPageRows is the number of entries shown at once on the library page.
+const libraryPageRows = 15
+
+// LibraryPageModel represents the model for the "Library" page.
+// It lists the downloaded files and supports sorting, filtering, deleting and opening them.
+type LibraryPageModel struct {
+	Cfg           *pages.ModelConfig
+	Library       *library.Library
+	Entries       []library.Entry // Entries matching the current filter, in display order.
+	Cursor        int
+	Sort          library.SortField
+	Ascending     bool
+	Filter        textinput.Model
+	Selected      map[string]bool // Paths of the entries selected for a playlist.
+	ConfirmDelete bool
+	Alert         string
+	Error         string
+	Time          time.Time
+}
+
+// LibraryPage initializes a new LibraryPageModel with the provided configuration.
+func LibraryPage(cfg *pages.ModelConfig) *LibraryPageModel {
+	m := &LibraryPageModel{
+		Cfg:      cfg,
+		Sort:     library.SortByDate,
+		Selected: make(map[string]bool),
+		Time:     time.Now(),
+	}
+
+	// Initialize the filter input with styles
+	input := textinput.New()
+	input.Placeholder = "Filter"
+
+	redStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff1f1f"))
+	input.Cursor.Style = lipgloss.NewStyle().Background(lipgloss.Color("#ff1f1f"))
+	input.Cursor.TextStyle = redStyle
+	input.TextStyle = redStyle
+	input.CompletionStyle = redStyle
+	input.PlaceholderStyle = redStyle
+	input.PromptStyle = redStyle
+
+	m.Filter = input
+	return m
+}
+
+// Init indexes the download directory and starts the clock.
+func (p *LibraryPageModel) Init() tea.Cmd {
+	p.Refresh()
+	return tick()
+}
+
+// Refresh re-indexes the download directory and re-applies the sort and filter.
+func (p *LibraryPageModel) Refresh() {
+	l, err := library.Index(library.DefaultDirectory)
+	if err != nil {
+		p.Cfg.Log.Error().Err(err).Msg("Failed to index library")
+		p.Error = err.Error()
+		return
+	}
+
+	p.Library = l
+	p.Error = ""
+	p.apply()
+}
+
+// Update handles incoming messages and updates the model state accordingly.
+func (p *LibraryPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tickMsg:
+		p.Time = time.Time(msg)
+		cmds = append(cmds, tick())
+
+	case tea.KeyMsg:
+		// The filter input takes every key while it is focused
+		if p.Filter.Focused() {
+			switch msg.Type {
+			case tea.KeyEsc, tea.KeyEnter:
+				p.Filter.Blur()
+			default:
+				ti, cmd := p.Filter.Update(msg)
+				p.Filter = ti
+				cmds = append(cmds, cmd)
+				p.apply()
+			}
+			return p, tea.Batch(cmds...)
+		}
+
+		// Any key other than a second "d" cancels a pending delete
+		if msg.String() != "d" {
+			p.ConfirmDelete = false
+		}
+
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc, tea.KeyBackspace:
+			return p.Cfg.Pages.SwitchToPreviousModel()
+		case tea.KeyUp:
+			if p.Cursor > 0 {
+				p.Cursor--
+			}
+		case tea.KeyDown:
+			if p.Cursor < len(p.Entries)-1 {
+				p.Cursor++
+			}
+		case tea.KeyEnter:
+			p.open()
+		case tea.KeySpace:
+			if p.Cursor < len(p.Entries) {
+				path := p.Entries[p.Cursor].Path
+				if p.Selected[path] {
+					delete(p.Selected, path)
+				} else {
+					p.Selected[path] = true
+				}
+			}
+		case tea.KeyRunes:
+			switch msg.String() {
+			case "/":
+				p.Filter.Focus()
+				cmds = append(cmds, textinput.Blink)
+			case "s":
+				p.Sort = p.Sort.Next()
+				p.apply()
+			case "o":
+				p.Ascending = !p.Ascending
+				p.apply()
+			case "r":
+				p.Refresh()
+			case "d":
+				p.delete()
+			case "p":
+				p.togglePin()
+			case "m":
+				p.savePlaylist()
+			}
+		}
+	}
+
+	return p, tea.Batch(cmds...)
+}
+
+// View renders the UI for the LibraryPageModel.
+func (p *LibraryPageModel) View() string {
+	w, h, _ := term.GetSize(int(os.Stdout.Fd()))
+
+	// Title
+	title := lipgloss.NewStyle().Bold(true).Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(Library.Name)
+
+	// Summary
+	var summary string
+	if p.Library != nil {
+		order := "desc"
+		if p.Ascending {
+			order = "asc"
+		}
+		summary = fmt.Sprintf("%d files, %s  |  sort: %s (%s)  |  selected: %d", len(p.Library.Entries), formatSize(p.Library.TotalSize()), p.Sort, order, len(p.Selected))
+	}
+
+	// Entries
+	var entries string
+	start := max(0, min(p.Cursor-libraryPageRows/2, len(p.Entries)-libraryPageRows))
+	end := min(len(p.Entries), start+libraryPageRows)
+	for i := start; i < end; i++ {
+		entry := p.Entries[i]
+		if i == p.Cursor {
+			entries += "> "
+		} else {
+			entries += "  "
+		}
+		if p.Selected[entry.Path] {
+			entries += "* "
+		} else {
+			entries += "  "
+		}
+		entries += fmt.Sprintf("%-48s %10s  %s  %8s\n",
+			truncate(entry.Title, 48),
+			formatSize(entry.Size),
+			entry.ModTime.Format("2006-01-02"),
+			formatDuration(entry.Duration),
+		)
+	}
+	if len(p.Entries) == 0 {
+		entries = "No files found in " + library.DefaultDirectory + "\n"
+	}
+	entries = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Render(entries)
+
+	// Filter
+	var filter string
+	if p.Filter.Focused() || p.Filter.Value() != "" {
+		filter = p.Filter.View()
+	}
+
+	// Alert and error handling
+	alert := p.Alert
+	if p.ConfirmDelete && p.Cursor < len(p.Entries) {
+		alert = "Press d again to delete " + filepath.Base(p.Entries[p.Cursor].Path)
+	}
+	if p.Error != "" {
+		alert = p.Error
+	}
+	alert = lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(alert)
+
+	help := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("enter: open  space: select  m: make playlist  /: filter  s: sort  o: order  p: pin  d: delete  r: refresh  esc: back")
+
+	style := lipgloss.NewStyle().
+		Width(w).
+		Height(h).
+		Align(lipgloss.Center, lipgloss.Center)
+
+	return style.Render(fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s", title, summary, filter, entries, alert, help))
+}
+
+// apply sorts the library and applies the filter, keeping the cursor in range.
+func (p *LibraryPageModel) apply() {
+	if p.Library == nil {
+		return
+	}
+
+	p.Library.Sort(p.Sort, p.Ascending)
+	p.Entries = p.Library.Filter(p.Filter.Value())
+	p.Cursor = max(0, min(p.Cursor, len(p.Entries)-1))
+}
+
+// open opens the selected entry in the configured player.
+func (p *LibraryPageModel) open() {
+	if p.Cursor >= len(p.Entries) {
+		return
+	}
+	entry := p.Entries[p.Cursor]
+
+	var player string
+	if cfg, err := config.GetConfig(); err == nil {
+		player = cfg.Library.Player
+	}
+
+	if err := library.Open(entry.Path, player); err != nil {
+		p.Cfg.Log.Error().Err(err).Str("path", entry.Path).Msg("Failed to open file")
+		p.Error = err.Error()
+		return
+	}
+	p.Alert = "Opened " + filepath.Base(entry.Path)
+}
+
+// delete deletes the selected entry once the deletion has been confirmed.
+func (p *LibraryPageModel) delete() {
+	if p.Cursor >= len(p.Entries) {
+		return
+	}
+	if !p.ConfirmDelete {
+		p.ConfirmDelete = true
+		return
+	}
+	p.ConfirmDelete = false
+
+	entry := p.Entries[p.Cursor]
+	if err := p.Library.Delete(entry); err != nil {
+		p.Cfg.Log.Error().Err(err).Str("path", entry.Path).Msg("Failed to delete file")
+		p.Error = err.Error()
+		return
+	}
+	p.Cfg.Log.Info().Str("path", entry.Path).Msg("Deleted file")
+	delete(p.Selected, entry.Path)
+
+	p.Alert = "Deleted " + filepath.Base(entry.Path)
+	p.apply()
+}
+
+// savePlaylist writes M3U8 and XSPF playlists of the selected entries, or of every
+// entry matching the filter if nothing is selected, in display order.
+func (p *LibraryPageModel) savePlaylist() {
+	var entries []playlist.Entry
+	for _, entry := range p.Entries {
+		if len(p.Selected) == 0 || p.Selected[entry.Path] {
+			entries = append(entries, playlist.Entry{
+				Path:     entry.Path,
+				Title:    entry.Title,
+				Duration: entry.Duration,
+			})
+		}
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	name := "playlist-" + time.Now().Format("20060102-150405")
+	for _, ext := range []string{".m3u8", ".xspf"} {
+		path := filepath.Join(library.DefaultDirectory, name+ext)
+		if err := playlist.Save(path, name, entries); err != nil {
+			p.Cfg.Log.Error().Err(err).Str("path", path).Msg("Failed to save playlist")
+			p.Error = err.Error()
+			return
+		}
+	}
+	p.Cfg.Log.Info().Str("playlist", name).Int("entries", len(entries)).Msg("Saved playlist")
+
+	p.Selected = make(map[string]bool)
+	p.Alert = fmt.Sprintf("Saved %s with %d entries", name, len(entries))
+}
+
+// togglePin adds or removes the selected entry from the retention keep-list.
+func (p *LibraryPageModel) togglePin() {
+	if p.Cursor >= len(p.Entries) {
+		return
+	}
+	entry := p.Entries[p.Cursor]
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		p.Error = err.Error()
+		return
+	}
+
+	keep := make([]string, 0, len(cfg.Retention.Keep))
+	for _, pattern := range cfg.Retention.Keep {
+		if pattern != entry.Path {
+			keep = append(keep, pattern)
+		}
+	}
+	pinned := len(keep) == len(cfg.Retention.Keep)
+	if pinned {
+		keep = append(keep, entry.Path)
+	}
+	cfg.Retention.Keep = keep
+
+	if err := config.WriteConfig(cfg); err != nil {
+		p.Cfg.Log.Error().Err(err).Msg("Failed to write config")
+		p.Error = err.Error()
+		return
+	}
+
+	if pinned {
+		p.Alert = "Pinned " + filepath.Base(entry.Path)
+	} else {
+		p.Alert = "Unpinned " + filepath.Base(entry.Path)
+	}
+}
+
+// formatSize formats a byte count using binary units.
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// formatDuration formats a duration as h:mm:ss or m:ss, or "-" if unknown.
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	d = d.Truncate(time.Second)
+	hours, minutes, seconds := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
+}
+
+// truncate shortens s to at most n runes, adding an ellipsis when it is cut.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
@@ -0,0 +1,236 @@
+package tui
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"sterben/features/image_convert"
+	"sterben/pkg/pages"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+// paletteExtensions are the files written when exporting a palette, see image_convert.WritePalette.
+var paletteExtensions = []string{".txt", ".json", ".css"}
+
+// PalettePageModel represents the model for the "Color Palette" page.
+// It extracts the dominant colors of an image, shows them as swatches and exports them.
+type PalettePageModel struct {
+	Cfg        *pages.ModelConfig
+	Input      textinput.Model
+	InputError string
+	Method     image_convert.PaletteMethod
+	Colors     int
+	Path       string
+	Image      image.Image // Decoded image at Path, kept so changing the options doesn't decode it again.
+	Swatches   []image_convert.Swatch
+	Alert      string
+	Time       time.Time
+
+	// The path and options of the last extraction asked for, older results are dropped.
+	extractKey string
+}
+
+// paletteMsg is a custom message used to deliver an extracted palette and the decoded image.
+type paletteMsg struct {
+	key      string
+	path     string
+	image    image.Image
+	swatches []image_convert.Swatch
+	err      error
+}
+
+// PalettePage initializes a new PalettePageModel with the provided configuration.
+func PalettePage(cfg *pages.ModelConfig) *PalettePageModel {
+	m := &PalettePageModel{
+		Cfg:    cfg,
+		Colors: 6,
+		Time:   time.Now(),
+	}
+
+	// Initialize the text input with styles
+	input := textinput.New()
+	input.Placeholder = "Enter Image Path"
+	input.Focus()
+
+	redStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff1f1f"))
+	input.Cursor.Style = lipgloss.NewStyle().Background(lipgloss.Color("#ff1f1f"))
+	input.Cursor.TextStyle = redStyle
+	input.TextStyle = redStyle
+	input.CompletionStyle = redStyle
+	input.PlaceholderStyle = redStyle
+	input.PromptStyle = redStyle
+
+	m.Input = input
+	return m
+}
+
+// Init initializes the model, setting up the blinking cursor for text input.
+func (p *PalettePageModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, tick())
+}
+
+// Update handles incoming messages and updates the model state accordingly.
+func (p *PalettePageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	// Update the time
+	switch msg := msg.(type) {
+	case tickMsg:
+		p.Time = time.Time(msg)
+		cmds = append(cmds, tick())
+	case paletteMsg:
+		if msg.image != nil && msg.path == p.Path {
+			p.Image = msg.image
+		}
+		if msg.key != p.extractKey {
+			return p, nil
+		}
+		p.Alert = ""
+		if msg.err != nil {
+			p.Cfg.Log.Error().Err(msg.err).Str("path", msg.path).Msg("Failed to extract palette")
+			p.InputError = msg.err.Error()
+			p.Swatches = nil
+			return p, nil
+		}
+		p.Swatches = msg.swatches
+		return p, nil
+	}
+
+	// Update text input
+	ti, cmd := p.Input.Update(msg)
+	p.Input = ti
+	cmds = append(cmds, cmd)
+
+	// Handle key messages
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			return p.Cfg.Pages.SwitchToPreviousModel()
+		case tea.KeyTab:
+			p.Method = p.Method.Next()
+			cmds = append(cmds, p.extract())
+		case tea.KeyUp:
+			if p.Colors < 16 {
+				p.Colors++
+				cmds = append(cmds, p.extract())
+			}
+		case tea.KeyDown:
+			if p.Colors > 1 {
+				p.Colors--
+				cmds = append(cmds, p.extract())
+			}
+		case tea.KeyEnter:
+			p.Path = p.Input.Value()
+			p.Image = nil
+			cmds = append(cmds, p.extract())
+		case tea.KeyCtrlO:
+			p.export()
+		}
+	}
+
+	return p, tea.Batch(cmds...)
+}
+
+// extract returns a command reading the palette of the image at Path with the current method
+// and number of colors, decoding the image only when it isn't loaded yet.
+func (p *PalettePageModel) extract() tea.Cmd {
+	p.InputError = ""
+	p.Alert = ""
+	if p.Path == "" {
+		if p.Input.Value() == "" {
+			p.InputError = "Please enter a valid Path"
+		}
+		return nil
+	}
+
+	key := fmt.Sprint(p.Path, p.Colors, p.Method)
+	p.extractKey = key
+	p.Alert = "Extracting..."
+	path, n, method, img := p.Path, p.Colors, p.Method, p.Image
+	return func() tea.Msg {
+		if img == nil {
+			decoded, err := image_convert.DecodeFile(path)
+			if err != nil {
+				return pages.PageMsg{Page: Palette, Msg: paletteMsg{key: key, path: path, err: err}}
+			}
+			img = decoded
+		}
+		swatches, err := image_convert.ExtractPalette(img, n, method)
+		return pages.PageMsg{Page: Palette, Msg: paletteMsg{key: key, path: path, image: img, swatches: swatches, err: err}}
+	}
+}
+
+// export writes the palette next to the image as hex, JSON and CSS.
+func (p *PalettePageModel) export() {
+	if len(p.Swatches) == 0 {
+		return
+	}
+
+	base := strings.TrimSuffix(p.Path, filepath.Ext(p.Path)) + "-palette"
+	var written []string
+	for _, ext := range paletteExtensions {
+		if err := image_convert.WritePalette(base+ext, p.Swatches); err != nil {
+			p.Cfg.Log.Error().Err(err).Str("path", base+ext).Msg("Failed to export palette")
+			p.InputError = err.Error()
+			return
+		}
+		written = append(written, base+ext)
+	}
+
+	p.Cfg.Log.Info().Strs("files", written).Msg("Exported palette")
+	p.Alert = "Exported " + strings.Join(written, ", ")
+}
+
+// View renders the UI for the PalettePageModel.
+func (p *PalettePageModel) View() string {
+	w, h, _ := term.GetSize(int(os.Stdout.Fd()))
+
+	// Title
+	title := lipgloss.NewStyle().Bold(true).Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(Palette.Name)
+
+	// Options
+	options := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render(
+		fmt.Sprintf("Method: %s  (tab)  |  Colors: %d  (up/down)", p.Method, p.Colors))
+
+	// Swatches
+	var swatches []string
+	for _, s := range p.Swatches {
+		block := lipgloss.NewStyle().Background(lipgloss.Color(s.Hex())).Render(strings.Repeat(" ", 8))
+		label := lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Render(fmt.Sprintf("  %s  %5.1f%%", s.Hex(), s.Weight*100))
+		swatches = append(swatches, block+label)
+	}
+
+	// Alert and error handling
+	alert := lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(p.Alert)
+	if p.InputError != "" {
+		alert = lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(p.InputError)
+	}
+
+	help := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("enter: extract  ctrl+o: export txt, json and css  esc: back")
+
+	style := lipgloss.NewStyle().
+		Width(w).
+		Height(h).
+		Align(lipgloss.Center, lipgloss.Center)
+
+	return style.Render(fmt.Sprintf("%s\n%s\n%s\n\n%s\n%s\n%s", title, p.Input.View(), options, strings.Join(swatches, "\n"), alert, help))
+}
+
+// Reset clears the input and the extracted palette.
+func (p *PalettePageModel) Reset() {
+	p.Input.Reset()
+	p.InputError = ""
+	p.Path = ""
+	p.Image = nil
+	p.extractKey = ""
+	p.Swatches = nil
+	p.Alert = ""
+}
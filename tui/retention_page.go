@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sterben/features/library"
+	"sterben/features/retention"
+	"sterben/pkg/config"
+	"sterben/pkg/pages"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+// RetentionPageModel represents the model for the "Cleanup" page.
+// It shows a dry-run report of the retention policy and applies it on request. Once the
+// config sets a limit, the policy also runs on its own at startup and after every download.
+type RetentionPageModel struct {
+	Cfg          *pages.ModelConfig
+	Library      *library.Library
+	Policy       retention.Policy
+	Report       *retention.Report
+	ConfirmApply bool
+	Alert        string
+	Error        string
+	Time         time.Time
+}
+
+// RetentionPage initializes a new RetentionPageModel with the provided configuration.
+func RetentionPage(cfg *pages.ModelConfig) *RetentionPageModel {
+	return &RetentionPageModel{
+		Cfg:  cfg,
+		Time: time.Now(),
+	}
+}
+
+// Init builds the dry-run report and starts the clock.
+func (p *RetentionPageModel) Init() tea.Cmd {
+	p.Alert = ""
+	p.Refresh()
+	return tick()
+}
+
+// Refresh re-indexes the download directory and plans the cleanup.
+func (p *RetentionPageModel) Refresh() {
+	p.ConfirmApply = false
+	p.Error = ""
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		p.Error = err.Error()
+		return
+	}
+	p.Policy = retention.NewPolicy(cfg.Retention.MaxTotalSizeMB, cfg.Retention.MaxAgeDays, cfg.Retention.Keep, cfg.Retention.Strategy)
+
+	l, err := library.Index(library.DefaultDirectory)
+	if err != nil {
+		p.Cfg.Log.Error().Err(err).Msg("Failed to index library")
+		p.Error = err.Error()
+		return
+	}
+
+	p.Library = l
+	p.Report = retention.Plan(l, p.Policy, time.Now())
+}
+
+// Update handles incoming messages and updates the model state accordingly.
+func (p *RetentionPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tickMsg:
+		p.Time = time.Time(msg)
+		cmds = append(cmds, tick())
+
+	case tea.KeyMsg:
+		// Any key other than a second "a" cancels a pending cleanup
+		if msg.String() != "a" {
+			p.ConfirmApply = false
+		}
+
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc, tea.KeyBackspace:
+			return p.Cfg.Pages.SwitchToPreviousModel()
+		case tea.KeyRunes:
+			switch msg.String() {
+			case "r":
+				p.Alert = ""
+				p.Refresh()
+			case "a":
+				p.apply()
+			}
+		}
+	}
+
+	return p, tea.Batch(cmds...)
+}
+
+// View renders the UI for the RetentionPageModel.
+func (p *RetentionPageModel) View() string {
+	w, h, _ := term.GetSize(int(os.Stdout.Fd()))
+
+	// Title
+	title := lipgloss.NewStyle().Bold(true).Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(Retention.Name)
+
+	// Policy
+	policy := fmt.Sprintf("max size: %s  |  max age: %s  |  strategy: %s  |  pinned: %d  |  automatic: %s",
+		formatLimit(p.Policy.MaxTotalSize > 0, formatSize(p.Policy.MaxTotalSize)),
+		formatLimit(p.Policy.MaxAge > 0, fmt.Sprintf("%d days", int(p.Policy.MaxAge.Hours()/24))),
+		p.Policy.Strategy,
+		len(p.Policy.Keep),
+		formatLimit(p.Policy.Enabled(), "after downloads"),
+	)
+
+	// Report
+	var report string
+	if p.Report != nil {
+		report = fmt.Sprintf("Dry run: %d files, %s freed (%s -> %s)\n\n",
+			len(p.Report.Deletions),
+			formatSize(p.Report.Freed()),
+			formatSize(p.Report.TotalBefore),
+			formatSize(p.Report.TotalAfter),
+		)
+		for i, deletion := range p.Report.Deletions {
+			if i == libraryPageRows {
+				report += fmt.Sprintf("... and %d more\n", len(p.Report.Deletions)-i)
+				break
+			}
+			report += fmt.Sprintf("%-48s %10s  %s\n",
+				truncate(filepath.Base(deletion.Entry.Path), 48),
+				formatSize(deletion.Entry.Size),
+				deletion.Reason,
+			)
+		}
+		if len(p.Report.Deletions) == 0 {
+			report += "Nothing to clean up\n"
+		}
+	}
+	report = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Render(report)
+
+	// Alert and error handling
+	alert := p.Alert
+	if p.ConfirmApply {
+		alert = fmt.Sprintf("Press a again to delete %d files", len(p.Report.Deletions))
+	}
+	if p.Error != "" {
+		alert = p.Error
+	}
+	alert = lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(alert)
+
+	help := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("a: apply  r: refresh  esc: back")
+
+	style := lipgloss.NewStyle().
+		Width(w).
+		Height(h).
+		Align(lipgloss.Center, lipgloss.Center)
+
+	return style.Render(fmt.Sprintf("%s\n%s\n\n%s\n%s\n%s", title, policy, report, alert, help))
+}
+
+// apply deletes the files in the report once the cleanup has been confirmed.
+func (p *RetentionPageModel) apply() {
+	if p.Report == nil || len(p.Report.Deletions) == 0 {
+		return
+	}
+	if !p.ConfirmApply {
+		p.ConfirmApply = true
+		return
+	}
+	p.ConfirmApply = false
+
+	deleted := len(p.Report.Deletions)
+	if err := retention.Apply(p.Library, p.Report, p.Cfg.Log); err != nil {
+		p.Error = err.Error()
+	}
+	p.Alert = fmt.Sprintf("Cleaned up %d files", deleted)
+
+	// Re-plan so the report reflects what is left on disk.
+	applyError := p.Error
+	p.Refresh()
+	if applyError != "" {
+		p.Error = applyError
+	}
+}
+
+// formatLimit returns value if the limit is enabled, "none" otherwise.
+func formatLimit(enabled bool, value string) string {
+	if !enabled {
+		return "none"
+	}
+	return value
+}
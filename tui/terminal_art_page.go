@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"sterben/features/image_convert"
+	"sterben/pkg/pages"
+	"sterben/pkg/termimage"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+// terminalArtExtensions are the files written when exporting, plain text and colored ANSI art.
+var terminalArtExtensions = []string{".txt", ".ans"}
+
+// terminalArtChrome is the number of lines the rest of the page takes around the art.
+const terminalArtChrome = 12
+
+// TerminalArtPageModel represents the model for the "Image to Terminal Art" page.
+// It draws an image with text characters fitted to the terminal and exports the result.
+type TerminalArtPageModel struct {
+	Cfg        *pages.ModelConfig
+	Input      textinput.Model
+	InputError string
+	Mode       termimage.Mode
+	Color      termimage.ColorDepth
+	Path       string
+	Image      image.Image
+	Alert      string
+	Time       time.Time
+
+	// The art drawn for renderKey, so it is only rendered again when the options or terminal change.
+	renderKey string
+	art       string
+}
+
+// TerminalArtPage initializes a new TerminalArtPageModel with the provided configuration.
+func TerminalArtPage(cfg *pages.ModelConfig) *TerminalArtPageModel {
+	m := &TerminalArtPageModel{
+		Cfg:  cfg,
+		Time: time.Now(),
+	}
+
+	// Initialize the text input with styles
+	input := textinput.New()
+	input.Placeholder = "Enter Image Path"
+	input.Focus()
+
+	redStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff1f1f"))
+	input.Cursor.Style = lipgloss.NewStyle().Background(lipgloss.Color("#ff1f1f"))
+	input.Cursor.TextStyle = redStyle
+	input.TextStyle = redStyle
+	input.CompletionStyle = redStyle
+	input.PlaceholderStyle = redStyle
+	input.PromptStyle = redStyle
+
+	m.Input = input
+	return m
+}
+
+// Init initializes the model, setting up the blinking cursor for text input.
+func (p *TerminalArtPageModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, tick())
+}
+
+// Update handles incoming messages and updates the model state accordingly.
+func (p *TerminalArtPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	// Update the time
+	switch msg := msg.(type) {
+	case tickMsg:
+		p.Time = time.Time(msg)
+		cmds = append(cmds, tick())
+	}
+
+	// Update text input
+	ti, cmd := p.Input.Update(msg)
+	p.Input = ti
+	cmds = append(cmds, cmd)
+
+	// Handle key messages
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			return p.Cfg.Pages.SwitchToPreviousModel()
+		case tea.KeyTab:
+			p.Mode = p.Mode.Next()
+		case tea.KeyCtrlT:
+			p.Color = p.Color.Next()
+		case tea.KeyEnter:
+			p.load()
+		case tea.KeyCtrlO:
+			p.export()
+		}
+	}
+
+	return p, tea.Batch(cmds...)
+}
+
+// load decodes the image at the input path.
+func (p *TerminalArtPageModel) load() {
+	p.InputError = ""
+	p.Alert = ""
+	if p.Input.Value() == "" {
+		p.InputError = "Please enter a valid Path"
+		return
+	}
+
+	img, err := image_convert.DecodeFile(p.Input.Value())
+	if err != nil {
+		p.Cfg.Log.Error().Err(err).Str("path", p.Input.Value()).Msg("Failed to load image")
+		p.InputError = err.Error()
+		p.Image = nil
+		return
+	}
+	p.Path = p.Input.Value()
+	p.Image = img
+	p.renderKey = ""
+}
+
+// options returns the render options for the current mode and color depth, fitted to the terminal.
+func (p *TerminalArtPageModel) options() termimage.Options {
+	w, h, _ := term.GetSize(int(os.Stdout.Fd()))
+	return termimage.Options{
+		Mode:      p.Mode,
+		Color:     p.Color,
+		Width:     max(1, w-4),
+		MaxHeight: max(1, h-terminalArtChrome),
+	}
+}
+
+// export writes the art next to the image as plain text and ANSI art, drawn as on the page.
+func (p *TerminalArtPageModel) export() {
+	if p.Image == nil {
+		return
+	}
+
+	base := strings.TrimSuffix(p.Path, filepath.Ext(p.Path))
+	var written []string
+	for _, ext := range terminalArtExtensions {
+		if err := termimage.Export(base+ext, p.Image, p.options()); err != nil {
+			p.Cfg.Log.Error().Err(err).Str("path", base+ext).Msg("Failed to export terminal art")
+			p.InputError = err.Error()
+			return
+		}
+		written = append(written, base+ext)
+	}
+
+	p.Cfg.Log.Info().Strs("files", written).Msg("Exported terminal art")
+	p.Alert = "Exported " + strings.Join(written, ", ")
+}
+
+// View renders the UI for the TerminalArtPageModel.
+func (p *TerminalArtPageModel) View() string {
+	w, h, _ := term.GetSize(int(os.Stdout.Fd()))
+
+	// Title
+	title := lipgloss.NewStyle().Bold(true).Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(TerminalArt.Name)
+
+	// Options
+	options := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render(
+		fmt.Sprintf("Mode: %s  (tab)  |  Color: %s  (ctrl+t)", p.Mode, p.Color))
+
+	// Art
+	if p.Image == nil {
+		p.art = ""
+	} else if opts := p.options(); fmt.Sprint(p.Path, opts) != p.renderKey {
+		p.renderKey = fmt.Sprint(p.Path, opts)
+		p.art = termimage.Render(p.Image, opts)
+	}
+
+	// Alert and error handling
+	alert := lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(p.Alert)
+	if p.InputError != "" {
+		alert = lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(p.InputError)
+	}
+
+	help := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("enter: load  ctrl+o: export txt and ans  esc: back")
+
+	style := lipgloss.NewStyle().
+		Width(w).
+		Height(h).
+		Align(lipgloss.Center, lipgloss.Center)
+
+	return style.Render(fmt.Sprintf("%s\n%s\n%s\n\n%s%s\n%s", title, p.Input.View(), options, p.art, alert, help))
+}
+
+// Reset clears the input and the loaded image.
+func (p *TerminalArtPageModel) Reset() {
+	p.Input.Reset()
+	p.InputError = ""
+	p.Path = ""
+	p.Image = nil
+	p.renderKey = ""
+	p.art = ""
+	p.Alert = ""
+}
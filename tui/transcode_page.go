@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"sterben/features/transcode"
+	"sterben/pkg/pages"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+// TranscodePageModel represents the model for the "Transcode" page.
+// It contains the configuration, file path input, selected preset and transcode state.
+type TranscodePageModel struct {
+	Cfg              *pages.ModelConfig
+	Input            textinput.Model
+	InputError       string
+	PresetCursor     int
+	Progress         transcode.Progress
+	TranscodeError   string
+	TranscodeOutput  string
+	TranscodeLoading bool
+	Time             time.Time
+}
+
+// TranscodePage initializes a new TranscodePageModel with the provided configuration.
+func TranscodePage(cfg *pages.ModelConfig) *TranscodePageModel {
+	m := &TranscodePageModel{
+		Cfg:  cfg,
+		Time: time.Now(),
+	}
+
+	// Initialize the text input with styles
+	input := textinput.New()
+	input.Placeholder = "Enter Media Path"
+	input.Focus()
+
+	redStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff1f1f"))
+	input.Cursor.Style = lipgloss.NewStyle().Background(lipgloss.Color("#ff1f1f"))
+	input.Cursor.TextStyle = redStyle
+	input.TextStyle = redStyle
+	input.CompletionStyle = redStyle
+	input.PlaceholderStyle = redStyle
+	input.PromptStyle = redStyle
+
+	m.Input = input
+	return m
+}
+
+// Init initializes the model, setting up the blinking cursor for text input.
+func (p *TranscodePageModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, tick())
+}
+
+// Update handles incoming messages and updates the model state accordingly.
+func (p *TranscodePageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	// Update the time
+	switch msg := msg.(type) {
+	case tickMsg:
+		p.Time = time.Time(msg)
+		cmds = append(cmds, tick())
+	}
+
+	// Update text input
+	ti, cmd := p.Input.Update(msg)
+	p.Input = ti
+	cmds = append(cmds, cmd)
+
+	// Handle key messages
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			return p.Cfg.Pages.SwitchToPreviousModel()
+		case tea.KeyUp:
+			if p.PresetCursor > 0 {
+				p.PresetCursor--
+			}
+		case tea.KeyDown:
+			if p.PresetCursor < len(transcode.Presets)-1 {
+				p.PresetCursor++
+			}
+		case tea.KeyEnter:
+			if p.TranscodeLoading {
+				return p, tea.Batch(cmds...)
+			}
+
+			p.InputError = ""
+			if p.Input.Value() == "" {
+				p.InputError = "Please enter a valid Path"
+				return p, tea.Batch(cmds...)
+			}
+			if !transcode.CheckIfFfmpegInstalled() {
+				p.InputError = "ffmpeg is not installed"
+				return p, tea.Batch(cmds...)
+			}
+
+			// Start transcoding in a goroutine, the tick refreshes the progress
+			p.TranscodeLoading = true
+			p.TranscodeError = ""
+			p.TranscodeOutput = ""
+			p.Progress = transcode.Progress{}
+			inputPath := p.Input.Value()
+			preset := transcode.Presets[p.PresetCursor]
+			go func() {
+				outputPath, err := transcode.Transcode(inputPath, preset, func(progress transcode.Progress) {
+					p.Progress = progress
+				})
+				if err != nil {
+					p.Cfg.Log.Error().Err(err).Str("preset", preset.Name).Msg("Failed to transcode")
+					p.TranscodeError = err.Error()
+				} else {
+					p.Cfg.Log.Info().Str("preset", preset.Name).Str("path", outputPath).Msg("Transcoded file")
+					p.TranscodeOutput = outputPath
+				}
+				p.TranscodeLoading = false
+			}()
+		}
+	}
+
+	return p, tea.Batch(cmds...)
+}
+
+// View renders the UI for the TranscodePageModel.
+func (p *TranscodePageModel) View() string {
+	w, h, _ := term.GetSize(int(os.Stdout.Fd()))
+
+	// Title
+	title := lipgloss.NewStyle().Bold(true).Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(Transcode.Name)
+
+	// Presets
+	var presets string
+	for i, preset := range transcode.Presets {
+		if i == p.PresetCursor {
+			presets += "> "
+		} else {
+			presets += "  "
+		}
+		presets += fmt.Sprintf("%-14s %s\n", preset.Name, preset.Description)
+	}
+	presets = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Render(presets)
+
+	// Input
+	var input string
+	if p.TranscodeLoading {
+		input = lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(transcodeStatus(p.Progress))
+	} else {
+		input = p.Input.View()
+	}
+
+	// Result
+	var result string
+	if p.TranscodeOutput != "" {
+		result = lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ffffff")).Render("Saved to " + p.TranscodeOutput)
+	}
+
+	// Error handling
+	err := lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(p.InputError)
+	if p.TranscodeError != "" {
+		err = lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(p.TranscodeError)
+	}
+
+	style := lipgloss.NewStyle().
+		Width(w).
+		Height(h).
+		Align(lipgloss.Center, lipgloss.Center)
+
+	return style.Render(fmt.Sprintf("%s\n%s\n%s\n%s\n%s", title, presets, input, result, err))
+}
+
+// Reset clears the input, progress and error states, resetting the page to its initial state.
+func (p *TranscodePageModel) Reset() {
+	p.Input.Reset()
+	p.InputError = ""
+	p.Progress = transcode.Progress{}
+	p.TranscodeError = ""
+	p.TranscodeOutput = ""
+	p.TranscodeLoading = false
+}
+
+// transcodeStatus formats a progress report for display.
+func transcodeStatus(progress transcode.Progress) string {
+	if progress.Duration <= 0 {
+		return fmt.Sprintf("Transcoding... %s (%d KiB, %s)", progress.OutTime.Truncate(time.Second), progress.TotalSize/1024, progress.Speed)
+	}
+	return fmt.Sprintf("Transcoding... %.1f%% (%d KiB, %s)", progress.Percent(), progress.TotalSize/1024, progress.Speed)
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"sterben/pkg/config"
 	"sterben/pkg/log"
 	"sterben/pkg/pages"
 	"sterben/tui/youtube"
@@ -21,6 +22,38 @@ var (
 		ID:   "image_to_icon",
 		Name: "Image to Icon",
 	}
+	Transcode pages.PageType = pages.PageType{
+		ID:   "transcode",
+		Name: "Transcode",
+	}
+	Library pages.PageType = pages.PageType{
+		ID:   "library",
+		Name: "Library",
+	}
+	Retention pages.PageType = pages.PageType{
+		ID:   "retention",
+		Name: "Cleanup",
+	}
+	BatchConvert pages.PageType = pages.PageType{
+		ID:   "batch_convert",
+		Name: "Batch Convert",
+	}
+	IconInspector pages.PageType = pages.PageType{
+		ID:   "icon_inspector",
+		Name: "Icon Inspector",
+	}
+	FormatConvert pages.PageType = pages.PageType{
+		ID:   "format_convert",
+		Name: "Convert Image Format",
+	}
+	Palette pages.PageType = pages.PageType{
+		ID:   "palette",
+		Name: "Color Palette",
+	}
+	TerminalArt pages.PageType = pages.PageType{
+		ID:   "terminal_art",
+		Name: "Image to Terminal Art",
+	}
 )
 
 type Tui struct {
@@ -52,6 +85,38 @@ func Initialize() (*Tui, error) {
 		Log:   l,
 		Pages: p,
 	})
+	batchConvertPage := BatchConvertPage(&pages.ModelConfig{
+		Log:   l,
+		Pages: p,
+	})
+	iconInspectorPage := IconInspectorPage(&pages.ModelConfig{
+		Log:   l,
+		Pages: p,
+	})
+	formatConvertPage := FormatConvertPage(&pages.ModelConfig{
+		Log:   l,
+		Pages: p,
+	})
+	palettePage := PalettePage(&pages.ModelConfig{
+		Log:   l,
+		Pages: p,
+	})
+	terminalArtPage := TerminalArtPage(&pages.ModelConfig{
+		Log:   l,
+		Pages: p,
+	})
+	transcodePage := TranscodePage(&pages.ModelConfig{
+		Log:   l,
+		Pages: p,
+	})
+	libraryPage := LibraryPage(&pages.ModelConfig{
+		Log:   l,
+		Pages: p,
+	})
+	retentionPage := RetentionPage(&pages.ModelConfig{
+		Log:   l,
+		Pages: p,
+	})
 
 	// Youtube Home Page
 	youtubePage := youtube.HomePage(&pages.ModelConfig{
@@ -63,11 +128,25 @@ func Initialize() (*Tui, error) {
 		Log:   l,
 		Pages: p,
 	})
+	// Youtube comments page
+	commentsPage := youtube.CommentsPage(&pages.ModelConfig{
+		Log:   l,
+		Pages: p,
+	})
 
 	p.AddModel(Home, homePage)
 	p.AddModel(ImageToIcon, imageToIconPage)
+	p.AddModel(BatchConvert, batchConvertPage)
+	p.AddModel(IconInspector, iconInspectorPage)
+	p.AddModel(FormatConvert, formatConvertPage)
+	p.AddModel(Palette, palettePage)
+	p.AddModel(TerminalArt, terminalArtPage)
+	p.AddModel(Transcode, transcodePage)
+	p.AddModel(Library, libraryPage)
+	p.AddModel(Retention, retentionPage)
 	p.AddModel(youtube.Home, youtubePage)
 	p.AddModel(youtube.SetUrl, setUrlPage)
+	p.AddModel(youtube.Comments, commentsPage)
 
 	return &Tui{
 		Log:   l,
@@ -79,6 +158,17 @@ func (y *Tui) Start() error {
 	y.Pages.SwitchModel(Home)
 
 	tea := tea.NewProgram(y.Pages, tea.WithAltScreen(), tea.WithMouseAllMotion())
+	y.Pages.SetProgram(tea)
+
+	// Enforce the retention limits on earlier downloads
+	if cfg, err := config.GetConfig(); err == nil {
+		go youtube.EnforceRetention(cfg.Retention, y.Log)
+	}
+
+	// Offer URLs copied to the clipboard
+	if w := y.watchClipboard(tea); w != nil {
+		defer w.Stop()
+	}
 
 	_, err := tea.Run()
 	if err != nil {
@@ -0,0 +1,258 @@
+package youtube
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sterben/features/library"
+	"sterben/features/youtube"
+	"sterben/pkg/config"
+	"sterben/pkg/pages"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+// commentsPageRows is the number of comments shown at once on the comments page.
+const commentsPageRows = 12
+
+// CommentsPageModel represents the model for the "Comments" page.
+// It shows the comment threads of the current video and exports them.
+type CommentsPageModel struct {
+	Cfg      *pages.ModelConfig
+	Url      string
+	VideoID  string
+	Comments []*youtube.Comment
+	Sort     youtube.CommentSort
+	Expanded map[string]bool // IDs of the comments whose replies are shown.
+	Cursor   int
+	Loading  bool
+	Alert    string
+	Error    string
+	Time     time.Time
+}
+
+// commentRow is a comment as displayed, with its depth in the thread.
+type commentRow struct {
+	comment *youtube.Comment
+	depth   int
+}
+
+// CommentsPage initializes a new CommentsPageModel with the provided configuration.
+func CommentsPage(cfg *pages.ModelConfig) *CommentsPageModel {
+	return &CommentsPageModel{
+		Cfg:      cfg,
+		Expanded: make(map[string]bool),
+		Time:     time.Now(),
+	}
+}
+
+// Init is called when the page is shown and starts the clock.
+func (p *CommentsPageModel) Init() tea.Cmd {
+	return tick()
+}
+
+// Load fetches the comments of the video at url in a goroutine, unless they are already loaded.
+func (p *CommentsPageModel) Load(url string, videoID string) {
+	if p.Loading || (url == p.Url && p.Comments != nil) {
+		return
+	}
+
+	p.Url = url
+	p.VideoID = videoID
+	p.Comments = nil
+	p.Expanded = make(map[string]bool)
+	p.Cursor = 0
+	p.Alert = ""
+	p.Error = ""
+
+	opts := youtube.CommentOptions{MaxComments: 500}
+	if cfg, err := config.GetConfig(); err == nil {
+		opts.MaxComments = cfg.Youtube.MaxComments
+		opts.MaxRepliesPerThread = cfg.Youtube.MaxRepliesPerThread
+	}
+
+	p.Loading = true
+	go func() {
+		comments, err := youtube.GetComments(url, opts)
+		if err != nil {
+			p.Cfg.Log.Error().Err(err).Str("url", url).Msg("Failed to get comments")
+			p.Error = err.Error()
+		} else {
+			youtube.SortComments(comments, p.Sort)
+			p.Comments = comments
+		}
+		p.Loading = false
+	}()
+}
+
+// Update handles incoming messages and updates the model state accordingly.
+func (p *CommentsPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tickMsg:
+		p.Time = time.Time(msg)
+		cmds = append(cmds, tick())
+
+	case tea.KeyMsg:
+		rows := p.rows()
+
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc, tea.KeyBackspace:
+			return p.Cfg.Pages.SwitchToPreviousModel()
+		case tea.KeyUp:
+			if p.Cursor > 0 {
+				p.Cursor--
+			}
+		case tea.KeyDown:
+			if p.Cursor < len(rows)-1 {
+				p.Cursor++
+			}
+		case tea.KeyEnter, tea.KeySpace:
+			if p.Cursor < len(rows) && len(rows[p.Cursor].comment.Replies) > 0 {
+				id := rows[p.Cursor].comment.ID
+				p.Expanded[id] = !p.Expanded[id]
+			}
+		case tea.KeyRunes:
+			switch msg.String() {
+			case "s":
+				p.Sort = (p.Sort + 1) % (youtube.SortCommentsByOldest + 1)
+				youtube.SortComments(p.Comments, p.Sort)
+				p.Cursor = 0
+			case "j":
+				p.export(".comments.json", youtube.ExportCommentsJSON)
+			case "c":
+				p.export(".comments.csv", youtube.ExportCommentsCSV)
+			}
+		}
+	}
+
+	return p, tea.Batch(cmds...)
+}
+
+// View renders the UI for the CommentsPageModel.
+func (p *CommentsPageModel) View() string {
+	w, h, _ := term.GetSize(int(os.Stdout.Fd()))
+
+	// Title
+	title := lipgloss.NewStyle().Bold(true).Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(Comments.Name)
+
+	// Comments
+	var comments string
+	switch {
+	case p.Loading:
+		comments = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff1f1f")).Render("Loading...")
+	case p.Comments != nil:
+		rows := p.rows()
+		summary := fmt.Sprintf("%d comments  |  sort: %s\n\n", youtube.CountComments(p.Comments), p.Sort)
+
+		start := max(0, min(p.Cursor-commentsPageRows/2, len(rows)-commentsPageRows))
+		end := min(len(rows), start+commentsPageRows)
+		for i := start; i < end; i++ {
+			comments += p.renderRow(rows[i], i == p.Cursor, w)
+		}
+		comments = summary + lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Render(comments)
+	}
+
+	// Alert and error handling
+	alert := p.Alert
+	if p.Error != "" {
+		alert = p.Error
+	}
+	alert = lipgloss.NewStyle().Padding(1).Foreground(lipgloss.Color("#ff1f1f")).Render(alert)
+
+	help := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("enter: replies  s: sort  j: export json  c: export csv  esc: back")
+
+	style := lipgloss.NewStyle().
+		Width(w).
+		Height(h).
+		Align(lipgloss.Center, lipgloss.Center)
+
+	return style.Render(fmt.Sprintf("%s\n%s\n%s\n%s", title, comments, alert, help))
+}
+
+// renderRow renders a single comment row, indented by its depth.
+func (p *CommentsPageModel) renderRow(row commentRow, selected bool, width int) string {
+	prefix := "  "
+	if selected {
+		prefix = "> "
+	}
+	indent := strings.Repeat("    ", row.depth)
+
+	marker := ""
+	if len(row.comment.Replies) > 0 {
+		if p.Expanded[row.comment.ID] {
+			marker = fmt.Sprintf(" [-%d]", len(row.comment.Replies))
+		} else {
+			marker = fmt.Sprintf(" [+%d]", len(row.comment.Replies))
+		}
+	}
+	if row.comment.IsPinned {
+		marker += " (pinned)"
+	}
+
+	header := fmt.Sprintf("%s%s%s  ♥ %d  %s%s", prefix, indent, row.comment.Author, row.comment.LikeCount, row.comment.Time().Format("2006-01-02"), marker)
+	text := strings.Join(strings.Fields(row.comment.Text), " ")
+	maxText := max(20, min(width, 120)-len(indent)-4)
+
+	return fmt.Sprintf("%s\n  %s  %s\n", header, indent, truncateText(text, maxText))
+}
+
+// rows returns the visible comments, with the replies of expanded threads.
+func (p *CommentsPageModel) rows() []commentRow {
+	var rows []commentRow
+	var add func(comments []*youtube.Comment, depth int)
+	add = func(comments []*youtube.Comment, depth int) {
+		for _, comment := range comments {
+			rows = append(rows, commentRow{comment: comment, depth: depth})
+			if p.Expanded[comment.ID] {
+				add(comment.Replies, depth+1)
+			}
+		}
+	}
+	add(p.Comments, 0)
+	return rows
+}
+
+// export writes the comments to the downloads directory using the given exporter.
+func (p *CommentsPageModel) export(ext string, exporter func(w io.Writer, comments []*youtube.Comment) error) {
+	if p.Comments == nil {
+		return
+	}
+
+	if err := os.MkdirAll(library.DefaultDirectory, 0755); err != nil {
+		p.Error = err.Error()
+		return
+	}
+
+	path := filepath.Join(library.DefaultDirectory, p.VideoID+ext)
+	f, err := os.Create(path)
+	if err != nil {
+		p.Error = err.Error()
+		return
+	}
+	defer f.Close()
+
+	if err := exporter(f, p.Comments); err != nil {
+		p.Cfg.Log.Error().Err(err).Str("path", path).Msg("Failed to export comments")
+		p.Error = err.Error()
+		return
+	}
+
+	p.Cfg.Log.Info().Str("path", path).Msg("Exported comments")
+	p.Alert = "Exported to " + path
+}
+
+// truncateText shortens s to at most n runes, adding an ellipsis when it is cut.
+func truncateText(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
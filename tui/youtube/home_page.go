@@ -3,8 +3,12 @@ package youtube
 import (
 	"fmt"
 	"os"
+	"sterben/features/library"
+	"sterben/features/transcode"
 	"sterben/features/youtube"
+	"sterben/pkg/config"
 	"sterben/pkg/pages"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -20,8 +24,13 @@ type HomePageModel struct {
 		List   []pages.PageType
 		Cursor pages.PageType
 	}
-	Alert string
-	Time  time.Time
+	Alert     string
+	Recording *youtube.RecordingProgress
+	Queue     []string // URLs waiting to be downloaded.
+	Time      time.Time
+
+	queueMutex   sync.Mutex
+	queueRunning bool
 }
 
 // tickMsg is a custom message used to update the time every second.
@@ -30,9 +39,18 @@ type tickMsg time.Time
 // downloadMsg is a custom message used to signal the result of the download process.
 type downloadMsg struct {
 	success bool
+	title   string // Title of a video downloaded from the queue, shown in the alert.
 	err     error
 }
 
+// alertMsg is a custom message used to show the progress of a running download.
+type alertMsg string
+
+// recordingMsg is a custom message used to update the recording indicator, nil hides it.
+type recordingMsg struct {
+	progress *youtube.RecordingProgress
+}
+
 // clearAlertMsg is a custom message used to clear the alert after a certain duration.
 type clearAlertMsg struct{}
 
@@ -46,6 +64,7 @@ func HomePage(cfg *pages.ModelConfig) *HomePageModel {
 	m.Options.List = []pages.PageType{
 		SetUrl,
 		Download,
+		Comments,
 	}
 
 	m.Options.Cursor = m.Options.List[0]
@@ -77,6 +96,8 @@ func (p *HomePageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case downloadMsg:
 		if msg.err != nil {
 			p.Alert = msg.err.Error()
+		} else if msg.title != "" {
+			p.Alert = "Downloaded " + msg.title
 		} else if msg.success {
 			p.Alert = "Downloaded!"
 		}
@@ -85,6 +106,12 @@ func (p *HomePageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return clearAlertMsg{}
 		})
 
+	case alertMsg:
+		p.Alert = string(msg)
+
+	case recordingMsg:
+		p.Recording = msg.progress
+
 	case clearAlertMsg:
 		p.Alert = ""
 
@@ -118,6 +145,16 @@ func (p *HomePageModel) View() string {
 		alert = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff1f1f")).Render(p.Alert)
 	}
 
+	// Download queue
+	if queued := p.QueueLength(); queued > 0 {
+		alert += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("#ff1f1f")).Render(fmt.Sprintf("Queued downloads: %d", queued))
+	}
+
+	// Recording indicator
+	if p.Recording != nil {
+		alert += "\n" + lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ff1f1f")).Render(recordingStatus(*p.Recording))
+	}
+
 	// Options
 	var options string
 	for _, opt := range p.Options.List {
@@ -130,7 +167,7 @@ func (p *HomePageModel) View() string {
 		switch opt {
 		case SetUrl:
 			setUrlPageModel := p.Cfg.Pages.Models[SetUrl].(*SetUrlPageModel)
-			if setUrlPageModel.MetaData != nil {
+			if setUrlPageModel.MetaData != nil || setUrlPageModel.Playlist != nil {
 				options += lipgloss.NewStyle().Foreground(lipgloss.Color("#ff1f1f")).Render(opt.Name, "(Reset)") + "\n"
 			} else {
 				options += opt.Name + "\n"
@@ -141,6 +178,11 @@ func (p *HomePageModel) View() string {
 	}
 	options = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Render(options)
 
+	// Thumbnail of the loaded video
+	if thumbnail := p.Cfg.Pages.Models[SetUrl].(*SetUrlPageModel).ThumbnailPreview(w - 4); thumbnail != "" {
+		options = thumbnail + "\n" + options
+	}
+
 	style := lipgloss.NewStyle().
 		Width(w).
 		Height(h).
@@ -195,6 +237,23 @@ func (p *HomePageModel) handleEnter() (tea.Model, tea.Cmd) {
 	case Download:
 		setUrlPageModel := p.Cfg.Pages.Models[SetUrl].(*SetUrlPageModel)
 
+		if setUrlPageModel.Playlist != nil {
+			p.Alert = "Downloading playlist..."
+			url := setUrlPageModel.Input.Value()
+			playlist := setUrlPageModel.Playlist
+			return p, func() tea.Msg {
+				paths, err := youtube.DownloadPlaylist(url, library.DefaultDirectory, playlist)
+				if err != nil {
+					return pages.PageMsg{Page: Home, Msg: downloadMsg{err: err}}
+				}
+				p.Cfg.Log.Info().Str("playlist", playlist.Title).Int("videos", len(paths)).Msg("Downloaded playlist")
+				if cfg, err := config.GetConfig(); err == nil {
+					EnforceRetention(cfg.Retention, p.Cfg.Log, paths...)
+				}
+				return pages.PageMsg{Page: Home, Msg: downloadMsg{success: true}}
+			}
+		}
+
 		if setUrlPageModel.MetaData == nil {
 			p.Alert = "No metadata available"
 			return p, tea.Batch(func() tea.Msg {
@@ -204,18 +263,188 @@ func (p *HomePageModel) handleEnter() (tea.Model, tea.Cmd) {
 		}
 
 		p.Alert = "Downloading..."
+		url := setUrlPageModel.Input.Value()
+		metaData := setUrlPageModel.MetaData
 		cmds := []tea.Cmd{
 			func() tea.Msg {
-				err := youtube.DownloadYoutubeVideo(setUrlPageModel.Input.Value(), "downloads")
+				err := p.download(url, metaData)
 				if err != nil {
-					return downloadMsg{err: err}
+					return pages.PageMsg{Page: Home, Msg: downloadMsg{err: err}}
 				}
-				return downloadMsg{success: true}
+				return pages.PageMsg{Page: Home, Msg: downloadMsg{success: true}}
 			},
 		}
 		return p, tea.Batch(cmds...)
 
+	case Comments:
+		setUrlPageModel := p.Cfg.Pages.Models[SetUrl].(*SetUrlPageModel)
+
+		if setUrlPageModel.MetaData == nil {
+			p.Alert = "No metadata available"
+			return p, tea.Batch(func() tea.Msg {
+				time.Sleep(3 * time.Second)
+				return clearAlertMsg{}
+			})
+		}
+
+		commentsPageModel, ok := p.Cfg.Pages.Models[Comments].(*CommentsPageModel)
+		if !ok {
+			p.Alert = "Comments are not available"
+			return p, tea.Batch(func() tea.Msg {
+				time.Sleep(3 * time.Second)
+				return clearAlertMsg{}
+			})
+		}
+		commentsPageModel.Load(setUrlPageModel.Input.Value(), setUrlPageModel.MetaData.ID)
+		return p.Cfg.Pages.SwitchModel(Comments)
+
 	default:
 		return p, nil
 	}
 }
+
+// download downloads the video, writing sidecars, using the library layout and
+// running the post-download transcode preset when they are enabled in the config.
+// It runs outside of Update, so progress is sent to the page as messages.
+func (p *HomePageModel) download(url string, metaData *youtube.VideoMetaData) error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		p.Cfg.Log.Warn().Err(err).Msg("Failed to get config, using plain download")
+		return youtube.DownloadYoutubeVideo(url, library.DefaultDirectory)
+	}
+
+	opts := youtube.SidecarOptions{
+		InfoJSON: cfg.Youtube.WriteInfoJSON,
+		NFO:      cfg.Youtube.WriteNFO,
+		Poster:   cfg.Youtube.WritePoster,
+	}
+
+	var mediaPath string
+	if metaData.IsLiveStream() {
+		mediaPath, err = p.record(url, metaData, cfg.Youtube.Live)
+		if err != nil {
+			return err
+		}
+		if err := youtube.WriteSidecars(mediaPath, metaData, opts); err != nil {
+			return err
+		}
+	} else if cfg.Youtube.LibraryLayout {
+		mediaPath, err = youtube.DownloadYoutubeVideoToLibrary(url, library.DefaultDirectory, metaData, opts)
+		if err != nil {
+			return err
+		}
+	} else {
+		mediaPath, err = youtube.DownloadYoutubeVideoToFile(url, library.DefaultDirectory)
+		if err != nil {
+			return err
+		}
+		if err := youtube.WriteSidecars(mediaPath, metaData, opts); err != nil {
+			return err
+		}
+	}
+	p.Cfg.Log.Info().Str("path", mediaPath).Msg("Downloaded video")
+	written := []string{mediaPath}
+
+	if cfg.Youtube.PostDownloadPreset != "" {
+		preset, ok := transcode.GetPreset(cfg.Youtube.PostDownloadPreset)
+		if !ok {
+			return fmt.Errorf("unknown transcode preset %q", cfg.Youtube.PostDownloadPreset)
+		}
+
+		p.Cfg.Pages.Send(Home, alertMsg("Transcoding ("+preset.Name+")..."))
+		outputPath, err := transcode.Transcode(mediaPath, preset, nil)
+		if err != nil {
+			return err
+		}
+		p.Cfg.Log.Info().Str("path", outputPath).Str("preset", preset.Name).Msg("Transcoded video")
+		written = append(written, outputPath)
+	}
+
+	EnforceRetention(cfg.Retention, p.Cfg.Log, written...)
+	return nil
+}
+
+// record records a live or upcoming stream, keeping the recording indicator up to date.
+func (p *HomePageModel) record(url string, metaData *youtube.VideoMetaData, cfg config.LiveConfig) (string, error) {
+	opts := youtube.LiveOptions{
+		WaitForStart: cfg.WaitForStart,
+		FromStart:    cfg.FromStart,
+		MaxDuration:  time.Duration(cfg.MaxDurationMinutes) * time.Minute,
+	}
+
+	p.Cfg.Pages.Send(Home, alertMsg("Recording..."))
+	p.Cfg.Pages.Send(Home, recordingMsg{&youtube.RecordingProgress{Waiting: true}})
+	defer p.Cfg.Pages.Send(Home, recordingMsg{})
+
+	mediaPath, err := youtube.RecordLiveStream(url, library.DefaultDirectory, metaData, opts, func(progress youtube.RecordingProgress) {
+		p.Cfg.Pages.Send(Home, recordingMsg{&progress})
+	})
+	if err != nil {
+		return "", err
+	}
+	p.Cfg.Log.Info().Str("path", mediaPath).Msg("Recorded live stream")
+
+	return mediaPath, nil
+}
+
+// recordingStatus formats the recording indicator shown while a live stream is recorded.
+func recordingStatus(progress youtube.RecordingProgress) string {
+	if progress.Waiting {
+		return "Waiting for stream to start..."
+	}
+
+	elapsed := progress.Elapsed.Truncate(time.Second)
+	return fmt.Sprintf("● REC %02d:%02d:%02d  %.1f MiB",
+		int(elapsed.Hours()), int(elapsed.Minutes())%60, int(elapsed.Seconds())%60,
+		float64(progress.Size)/(1024*1024),
+	)
+}
+
+// Enqueue adds url to the download queue and starts working through the queue
+// in a goroutine if it is not running yet.
+func (p *HomePageModel) Enqueue(url string) {
+	p.queueMutex.Lock()
+	defer p.queueMutex.Unlock()
+
+	p.Queue = append(p.Queue, url)
+	p.Cfg.Log.Info().Str("url", url).Msg("Queued download")
+
+	if !p.queueRunning {
+		p.queueRunning = true
+		go p.processQueue()
+	}
+}
+
+// QueueLength returns the number of downloads waiting in the queue.
+func (p *HomePageModel) QueueLength() int {
+	p.queueMutex.Lock()
+	defer p.queueMutex.Unlock()
+
+	return len(p.Queue)
+}
+
+// processQueue downloads the queued URLs one after another until the queue is empty.
+func (p *HomePageModel) processQueue() {
+	for {
+		p.queueMutex.Lock()
+		if len(p.Queue) == 0 {
+			p.queueRunning = false
+			p.queueMutex.Unlock()
+			return
+		}
+		url := p.Queue[0]
+		p.Queue = p.Queue[1:]
+		p.queueMutex.Unlock()
+
+		metaData, err := youtube.GetVideoMetaData(url)
+		if err == nil {
+			err = p.download(url, metaData)
+		}
+		if err != nil {
+			p.Cfg.Log.Error().Err(err).Str("url", url).Msg("Failed to download queued video")
+			p.Cfg.Pages.Send(Home, downloadMsg{err: err})
+			continue
+		}
+		p.Cfg.Pages.Send(Home, downloadMsg{success: true, title: metaData.Title})
+	}
+}
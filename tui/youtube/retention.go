@@ -0,0 +1,31 @@
+package youtube
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sterben/features/library"
+	"sterben/features/retention"
+	"sterben/pkg/config"
+	"sterben/pkg/log"
+	"time"
+)
+
+// EnforceRetention deletes the downloads the retention policy of the config doesn't allow,
+// never the keep paths, which were just downloaded. Nothing happens when the config sets no
+// limits, and failures are only logged so they never fail a download.
+func EnforceRetention(cfg config.RetentionConfig, l log.Log, keep ...string) {
+	pinned := append([]string{}, cfg.Keep...)
+	for _, path := range keep {
+		pinned = append(pinned, filepath.Clean(path))
+	}
+	policy := retention.NewPolicy(cfg.MaxTotalSizeMB, cfg.MaxAgeDays, pinned, cfg.Strategy)
+
+	report, err := retention.Enforce(library.DefaultDirectory, policy, time.Now(), l)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		l.Error().Err(err).Msg("Failed to enforce retention policy")
+	}
+	if report != nil && len(report.Deletions) > 0 {
+		l.Info().Int("files", len(report.Deletions)).Int64("freed", report.Freed()).Msg("Enforced retention policy")
+	}
+}
@@ -2,9 +2,11 @@ package youtube
 
 import (
 	"fmt"
+	"image"
 	"os"
 	"sterben/features/youtube"
 	"sterben/pkg/pages"
+	"sterben/pkg/termimage"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -20,9 +22,24 @@ type SetUrlPageModel struct {
 	Input           textinput.Model
 	InputError      string
 	MetaData        *youtube.VideoMetaData
+	Playlist        *youtube.PlaylistMetaData
 	MetaDataError   string
 	MetaDataLoading bool
+	Thumbnail       image.Image // Video thumbnail, fetched once the metadata is loaded.
 	Time            time.Time
+
+	// Thumbnail drawn thumbnailWidth columns wide, rendered again when the width changes.
+	thumbnailWidth   int
+	thumbnailPreview string
+}
+
+// thumbnailPreviewWidth is the largest width of the thumbnail preview, in terminal columns.
+const thumbnailPreviewWidth = 48
+
+// thumbnailMsg is a custom message used to deliver the thumbnail fetched for metadata.
+type thumbnailMsg struct {
+	metadata  *youtube.VideoMetaData
+	thumbnail image.Image
 }
 
 // SetUrlPage initializes a new SetUrlPageModel with the provided configuration.
@@ -64,6 +81,13 @@ func (p *SetUrlPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tickMsg:
 		p.Time = time.Time(msg)
 		cmds = append(cmds, tick())
+	case thumbnailMsg:
+		// Delivered after the page was left, unless it was reset for another video since.
+		if msg.metadata == p.MetaData {
+			p.Thumbnail = msg.thumbnail
+			p.thumbnailPreview = ""
+		}
+		return p, nil
 	}
 
 	// Update text input
@@ -72,8 +96,12 @@ func (p *SetUrlPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds = append(cmds, cmd)
 
 	// Check if metadata is already loaded
-	if p.MetaData != nil {
-		return p.Cfg.Pages.SwitchToPreviousModel()
+	if p.MetaData != nil || p.Playlist != nil {
+		m, cmd := p.Cfg.Pages.SwitchToPreviousModel()
+		if p.MetaData != nil {
+			cmd = tea.Batch(cmd, p.loadThumbnail(p.MetaData))
+		}
+		return m, cmd
 	}
 
 	// Handle key messages
@@ -91,18 +119,7 @@ func (p *SetUrlPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				// Start loading metadata in a goroutine
-				p.MetaDataLoading = true
-				go func() {
-					metadata, err := youtube.GetVideoMetaData(p.Input.Value())
-					if err != nil {
-						p.MetaDataError = err.Error()
-						p.MetaDataLoading = false
-					} else {
-						p.MetaData = metadata
-						p.MetaDataLoading = false
-					}
-				}()
-
+				p.LoadMetaData()
 				return p, tea.Batch(cmds...)
 			}
 		} else {
@@ -149,10 +166,67 @@ func (p *SetUrlPageModel) View() string {
 	return style.Render(fmt.Sprintf("%s\n%s\n%s\n", title, input, err))
 }
 
+// LoadMetaData starts loading the video or playlist metadata for the current input in a goroutine.
+func (p *SetUrlPageModel) LoadMetaData() {
+	url := p.Input.Value()
+	p.MetaDataError = ""
+	p.MetaDataLoading = true
+
+	go func() {
+		if youtube.IsPlaylistURL(url) {
+			playlist, err := youtube.GetPlaylistMetaData(url)
+			if err != nil {
+				p.MetaDataError = err.Error()
+			} else {
+				p.Playlist = playlist
+			}
+			p.MetaDataLoading = false
+			return
+		}
+
+		metadata, err := youtube.GetVideoMetaData(url)
+		if err != nil {
+			p.MetaDataError = err.Error()
+			p.MetaDataLoading = false
+		} else {
+			p.MetaData = metadata
+			p.MetaDataLoading = false
+		}
+	}()
+}
+
+// loadThumbnail returns a command fetching the thumbnail of metadata for the page.
+func (p *SetUrlPageModel) loadThumbnail(metadata *youtube.VideoMetaData) tea.Cmd {
+	return func() tea.Msg {
+		thumbnail, err := youtube.FetchThumbnail(metadata)
+		if err != nil {
+			p.Cfg.Log.Error().Err(err).Str("id", metadata.ID).Msg("Failed to load thumbnail")
+			return nil
+		}
+		return pages.PageMsg{Page: SetUrl, Msg: thumbnailMsg{metadata, thumbnail}}
+	}
+}
+
+// ThumbnailPreview returns the thumbnail drawn at most width columns wide, or "" if there is none.
+func (p *SetUrlPageModel) ThumbnailPreview(width int) string {
+	if p.Thumbnail == nil || width <= 0 {
+		return ""
+	}
+	width = min(thumbnailPreviewWidth, width)
+	if width != p.thumbnailWidth || p.thumbnailPreview == "" {
+		p.thumbnailWidth = width
+		p.thumbnailPreview = termimage.Render(p.Thumbnail, termimage.Options{Width: width})
+	}
+	return p.thumbnailPreview
+}
+
 // Reset clears the input, metadata, and error states, resetting the page to its initial state.
 func (p *SetUrlPageModel) Reset() {
 	p.Input.Reset()
 	p.MetaData = nil
+	p.Playlist = nil
+	p.Thumbnail = nil
+	p.thumbnailPreview = ""
 	p.MetaDataError = ""
 	p.MetaDataLoading = false
 	p.InputError = ""
@@ -3,6 +3,7 @@ package youtube
 import (
 	"fmt"
 	"sterben/features/youtube"
+	"sterben/pkg/config"
 	"sterben/pkg/log"
 	"sterben/pkg/pages"
 
@@ -23,6 +24,10 @@ var (
 		ID:   "youtube_download",
 		Name: "Download",
 	}
+	Comments pages.PageType = pages.PageType{
+		ID:   "youtube_comments",
+		Name: "Comments",
+	}
 )
 
 type YoutubeTui struct {
@@ -79,8 +84,15 @@ func Initialize() (*YoutubeTui, error) {
 		Pages: p,
 	})
 
+	// Comments Page
+	commentsPage := CommentsPage(&pages.ModelConfig{
+		Log:   l3,
+		Pages: p,
+	})
+
 	p.AddModel(Home, homePage)
 	p.AddModel(SetUrl, setUrlPage)
+	p.AddModel(Comments, commentsPage)
 
 	return &YoutubeTui{
 		Log:   l,
@@ -92,6 +104,12 @@ func (y *YoutubeTui) Start() error {
 	y.Pages.SwitchModel(Home)
 
 	tea := tea.NewProgram(y.Pages, tea.WithAltScreen(), tea.WithMouseAllMotion())
+	y.Pages.SetProgram(tea)
+
+	// Enforce the retention limits on earlier downloads
+	if cfg, err := config.GetConfig(); err == nil {
+		go EnforceRetention(cfg.Retention, y.Log)
+	}
 
 	_, err := tea.Run()
 	if err != nil {
@@ -132,6 +150,13 @@ func GetDebugData(p *pages.Pages) string {
 		s += fmt.Sprintf("Views: %d\n", setUrlPageModel.MetaData.ViewCount)
 		s += fmt.Sprintf("Duration: %d\n", setUrlPageModel.MetaData.Duration)
 	}
+	if setUrlPageModel.Playlist != nil {
+		s += "Playlist\n"
+		s += "---------\n"
+		s += "ID: " + setUrlPageModel.Playlist.ID + "\n"
+		s += "Title: " + setUrlPageModel.Playlist.Title + "\n"
+		s += fmt.Sprintf("Videos: %d\n", len(setUrlPageModel.Playlist.Entries))
+	}
 
 	return lipgloss.NewStyle().Foreground(lipgloss.Color("#0cd3ff")).Render(s)
 }